@@ -0,0 +1,333 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+	"github.com/guessi/eks-ami-finder/pkg/finder"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/urfave/cli/v3"
+)
+
+// AMI IDs are either 8 (legacy) or 17 hexadecimal digits
+var amiIdRegex = regexp.MustCompile(`\bami-(?:[0-9a-f]{17}|[0-9a-f]{8})\b`)
+
+// Files picked up when walking directories, Terraform/OpenTofu, eksctl and Karpenter manifests
+var bumpFileExtensions = []string{".tf", ".tfvars", ".hcl", ".json", ".yaml", ".yml"}
+
+var BumpFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Print the diff without rewriting any file",
+	},
+}
+
+// collectBumpFiles expands directories into the files worth scanning, hidden directories
+// (.git, .terraform, ...) are skipped. Files given explicitly are always scanned.
+func collectBumpFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if p != path && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if slices.Contains(bumpFileExtensions, filepath.Ext(p)) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slices.Sort(files)
+	return slices.Compact(files), nil
+}
+
+// locateImages inspects imageIds in each region, an AMI is attributed to the first region it is found in
+func locateImages(ctx context.Context, regions, imageIds []string) (map[string]finder.Inspection, error) {
+	inspections := make([][]finder.Inspection, len(regions))
+	errs := make([]error, len(regions))
+	forEachRegion(regions, func(idx int, region string) {
+		svc, err := trustedImageClient(ctx, region)
+		if err != nil {
+			errs[idx] = err
+			return
+		}
+		inspections[idx], errs[idx] = finder.New(svc).Inspect(ctx, region, imageIds)
+	})
+
+	located := make(map[string]finder.Inspection, len(imageIds))
+	for idx, region := range regions {
+		if errs[idx] != nil {
+			return nil, fmt.Errorf("[%s] %v", region, errs[idx])
+		}
+		for _, i := range inspections[idx] {
+			if _, ok := located[i.Image.ImageId]; !ok {
+				located[i.Image.ImageId] = i
+			}
+		}
+	}
+
+	return located, nil
+}
+
+// lineDiff returns a minimal line-by-line diff, replacing AMI IDs never changes the number of lines
+func lineDiff(path, before, after string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", path, path)
+
+	beforeLines := strings.Split(before, "\n")
+	afterLines := strings.Split(after, "\n")
+	for idx := range beforeLines {
+		if beforeLines[idx] != afterLines[idx] {
+			fmt.Fprintf(&b, "@@ -%d +%d @@\n-%s\n+%s\n", idx+1, idx+1, beforeLines[idx], afterLines[idx])
+		}
+	}
+
+	return b.String()
+}
+
+func renderBumps(w io.Writer, format string, bumps []amiBump) error {
+	if bumps == nil {
+		bumps = []amiBump{}
+	}
+	output := amiBumpOutput{
+		SchemaVersion: outputSchemaVersion,
+		Results:       bumps,
+	}
+
+	switch format {
+	case "", constants.OutputFormatTable:
+		t := table.NewWriter()
+		t.SetOutputMirror(w)
+		t.AppendHeader(table.Row{
+			"Region",
+			"AMI Type",
+			"Kubernetes Version",
+			"From",
+			"To",
+			"To Name",
+		})
+		for _, b := range bumps {
+			t.AppendRow(table.Row{
+				b.Region,
+				b.AmiType,
+				b.KubernetesVersion,
+				b.From,
+				b.To,
+				b.ToName,
+			})
+		}
+		t.Style().Format.Header = text.FormatDefault
+		t.Render()
+		return nil
+	case constants.OutputFormatJSON:
+		return encodeJSON(w, output)
+	case constants.OutputFormatYAML:
+		return encodeYAML(w, output)
+	case constants.OutputFormatCSV:
+		rows := make([][]string, 0, len(bumps))
+		for _, b := range bumps {
+			rows = append(rows, []string{
+				b.Region,
+				b.AmiType,
+				b.KubernetesVersion,
+				b.From,
+				b.FromName,
+				b.To,
+				b.ToName,
+			})
+		}
+		return writeCSV(w, []string{
+			"region",
+			"amiType",
+			"kubernetesVersion",
+			"from",
+			"fromName",
+			"to",
+			"toName",
+		}, rows)
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// Bump finds the AMI IDs referenced by the given files, identifies them, and rewrites them
+// to the newest release of the same AMI type and Kubernetes version.
+func Bump(ctx context.Context, c *cli.Command) error {
+	ctx, cancel := context.WithTimeout(ctx, c.Duration("timeout"))
+	defer cancel()
+
+	if err := applySharedFlags(c); err != nil {
+		return err
+	}
+
+	paths := c.Args().Slice()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	files, err := collectBumpFiles(paths)
+	if err != nil {
+		return err
+	}
+
+	contents := make(map[string]string, len(files))
+	var imageIds []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		contents[file] = string(data)
+		for _, id := range amiIdRegex.FindAllString(contents[file], -1) {
+			if !slices.Contains(imageIds, id) {
+				imageIds = append(imageIds, id)
+			}
+		}
+	}
+	if len(imageIds) == 0 {
+		fmt.Fprintf(os.Stderr, "No AMI ID found in %d file(s)\n", len(files))
+		return nil
+	}
+	slices.Sort(imageIds)
+
+	regions, err := parseRegions(c.String("region"))
+	if err != nil {
+		return err
+	}
+
+	located, err := locateImages(ctx, regions, imageIds)
+	if err != nil {
+		return err
+	}
+
+	// Only official Amazon EKS optimized AMIs are bumped, anything else is left untouched
+	var candidates []finder.Inspection
+	for _, id := range imageIds {
+		i, ok := located[id]
+		switch {
+		case !ok:
+			fmt.Fprintf(os.Stderr, "Note: skipping %s, not found in %s\n", id, strings.Join(regions, ", "))
+		case !i.Identified:
+			fmt.Fprintf(os.Stderr, "Note: skipping %s, %s is not an Amazon EKS optimized AMI\n", id, i.Image.Name)
+		case !i.Official:
+			fmt.Fprintf(os.Stderr, "Note: skipping %s, owner %s is not the official owner in %s\n", id, i.Image.OwnerId, i.Region)
+		default:
+			candidates = append(candidates, i)
+		}
+	}
+
+	inputs := make([]amiSearchInputSpec, 0, len(candidates))
+	for _, i := range candidates {
+		inputs = append(inputs, amiSearchInputSpec{
+			AWS_REGION:         i.Region,
+			AMI_TYPE:           i.Identity.AmiType,
+			KUBERNETES_VERSION: i.Identity.KubernetesVersion,
+			MAX_RESULTS:        1,
+			AUTO_MODE:          i.Identity.AutoMode,
+			DEBUG_MODE:         c.Bool("debug"),
+		})
+	}
+
+	newest, errs := newestForEach(ctx, inputs)
+
+	var bumps []amiBump
+	replacements := make(map[string]string, len(candidates))
+	for idx, i := range candidates {
+		if errs[idx] != nil {
+			fmt.Fprintf(os.Stderr, "Warning: [%s] %s\n", lockSearchError(inputs[idx], errs[idx]).label(), errs[idx])
+			continue
+		}
+		if newest[idx].ImageId == i.Image.ImageId {
+			continue
+		}
+		replacements[i.Image.ImageId] = newest[idx].ImageId
+		bumps = append(bumps, amiBump{
+			Region:            i.Region,
+			AmiType:           i.Identity.AmiType,
+			KubernetesVersion: i.Identity.KubernetesVersion,
+			From:              i.Image.ImageId,
+			FromName:          i.Image.Name,
+			To:                newest[idx].ImageId,
+			ToName:            newest[idx].Name,
+		})
+	}
+
+	if len(bumps) == 0 {
+		fmt.Fprintf(os.Stderr, "All %d official AMI(s) are up to date\n", len(candidates))
+		return nil
+	}
+
+	if err := renderBumps(os.Stdout, c.String("output"), bumps); err != nil {
+		return fmt.Errorf("unable to render results: %v", err)
+	}
+
+	// Keep stdout machine-readable for structured output formats
+	diffOut := os.Stdout
+	if format := c.String("output"); format != "" && format != constants.OutputFormatTable {
+		diffOut = os.Stderr
+	}
+
+	changed := 0
+	for _, file := range files {
+		before := contents[file]
+		after := amiIdRegex.ReplaceAllStringFunc(before, func(id string) string {
+			if to, ok := replacements[id]; ok {
+				return to
+			}
+			return id
+		})
+		if after == before {
+			continue
+		}
+		changed++
+
+		if c.Bool("dry-run") {
+			fmt.Fprint(diffOut, lineDiff(file, before, after))
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, []byte(after), info.Mode().Perm()); err != nil {
+			return fmt.Errorf("unable to rewrite %s: %v", file, err)
+		}
+	}
+
+	if c.Bool("dry-run") {
+		fmt.Fprintf(os.Stderr, "Dry run, %d file(s) would be rewritten\n", changed)
+	} else {
+		fmt.Fprintf(os.Stderr, "Rewrote %d file(s)\n", changed)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/guessi/eks-ami-finder/pkg/cache"
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/urfave/cli/v3"
+)
+
+// cacheDir returns the --cache-dir input, or the default cache directory when empty
+func cacheDir(dir string) (string, error) {
+	if dir != "" {
+		return dir, nil
+	}
+	return cache.Dir()
+}
+
+// cacheStore opens the cache directory regardless of whether caching is enabled for searches
+func cacheStore(c *cli.Command) (*cache.Store, error) {
+	dir, err := cacheDir(c.String("cache-dir"))
+	if err != nil {
+		return nil, err
+	}
+	return cache.New(dir, c.Duration("cache-ttl"), false), nil
+}
+
+func renderCacheStats(w io.Writer, format string, s cacheStats) error {
+	switch format {
+	case "", constants.OutputFormatTable:
+		t := table.NewWriter()
+		t.SetOutputMirror(w)
+		t.AppendRows([]table.Row{
+			{"Directory", s.Dir},
+			{"TTL", s.TTL},
+			{"Entries", s.Entries},
+			{"Fresh", s.Fresh},
+			{"Stale", s.Stale},
+			{"Invalid", s.Invalid},
+			{"Images", s.Images},
+			{"Size", fmt.Sprintf("%d bytes", s.Bytes)},
+			{"Oldest", s.Oldest},
+			{"Newest", s.Newest},
+		})
+		t.Style().Format.Header = text.FormatDefault
+		t.Render()
+		return nil
+	case constants.OutputFormatJSON:
+		return encodeJSON(w, cacheStatsOutput{SchemaVersion: outputSchemaVersion, Stats: s})
+	case constants.OutputFormatYAML:
+		return encodeYAML(w, cacheStatsOutput{SchemaVersion: outputSchemaVersion, Stats: s})
+	case constants.OutputFormatCSV:
+		return writeCSV(w, []string{
+			"dir",
+			"ttl",
+			"entries",
+			"fresh",
+			"stale",
+			"invalid",
+			"images",
+			"bytes",
+			"oldest",
+			"newest",
+		}, [][]string{{
+			s.Dir,
+			s.TTL,
+			strconv.Itoa(s.Entries),
+			strconv.Itoa(s.Fresh),
+			strconv.Itoa(s.Stale),
+			strconv.Itoa(s.Invalid),
+			strconv.Itoa(s.Images),
+			strconv.FormatInt(s.Bytes, 10),
+			s.Oldest,
+			s.Newest,
+		}})
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+func CacheStats(ctx context.Context, c *cli.Command) error {
+	store, err := cacheStore(c)
+	if err != nil {
+		return err
+	}
+
+	st, err := store.Stats()
+	if err != nil {
+		return err
+	}
+
+	s := cacheStats{
+		Dir:     st.Dir,
+		TTL:     c.Duration("cache-ttl").String(),
+		Entries: st.Entries,
+		Fresh:   st.Fresh,
+		Stale:   st.Stale,
+		Invalid: st.Invalid,
+		Images:  st.Images,
+		Bytes:   st.Bytes,
+		Oldest:  formatTime(st.Oldest),
+		Newest:  formatTime(st.Newest),
+	}
+
+	if err := renderCacheStats(os.Stdout, c.String("output"), s); err != nil {
+		return fmt.Errorf("unable to render results: %v", err)
+	}
+	return nil
+}
+
+func CacheClear(ctx context.Context, c *cli.Command) error {
+	store, err := cacheStore(c)
+	if err != nil {
+		return err
+	}
+
+	removed, err := store.Clear()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Removed %d cache entries\n", removed)
+	return nil
+}
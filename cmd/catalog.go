@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/guessi/eks-ami-finder/pkg/cache"
+	"github.com/guessi/eks-ami-finder/pkg/catalog"
+	"github.com/guessi/eks-ami-finder/pkg/finder"
+	"github.com/urfave/cli/v3"
+)
+
+var CatalogExportFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "file",
+		Aliases: []string{"f"},
+		Value:   catalog.DefaultPath,
+		Usage:   "Catalog file to write",
+	},
+	&cli.StringFlag{
+		Name:    "signing-key",
+		Usage:   "Private key to sign the catalog with, the signature is written next to it with a .sig extension, see \"catalog keygen\"",
+		Sources: cli.EnvVars("EKS_AMI_FINDER_CATALOG_SIGNING_KEY"),
+	},
+}
+
+var CatalogKeygenFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "private-key",
+		Value: "eks-ami-catalog.key",
+		Usage: "File to write the private key to, keep it where catalogs are exported",
+	},
+	&cli.StringFlag{
+		Name:  "public-key",
+		Value: "eks-ami-catalog.pub",
+		Usage: "File to write the public key to, distribute it with --catalog-public-key wherever catalogs are used",
+	},
+}
+
+// imageSource is where image lookups go, set up once from the shared flags before any lookup starts
+var imageSource struct {
+	cache   *cache.Store    // nil when caching is disabled
+	catalog *catalog.Bundle // --catalog, or the imported catalog when offline
+	offline bool
+}
+
+// importedCatalogPath is where "catalog import" installs the catalog used by offline lookups
+func importedCatalogPath(c *cli.Command) (string, error) {
+	dir, err := cacheDir(c.String("cache-dir"))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "catalog", catalog.DefaultPath), nil
+}
+
+// applyImageSource sets up the cache and the catalog image lookups go through
+func applyImageSource(c *cli.Command) error {
+	imageSource.offline = c.Bool("offline")
+
+	if ttl := c.Duration("cache-ttl"); imageSource.offline || ttl > 0 {
+		dir, err := cacheDir(c.String("cache-dir"))
+		switch {
+		case err == nil:
+			imageSource.cache = cache.New(dir, ttl, imageSource.offline)
+		case imageSource.offline:
+			return err
+		default:
+			// The cache is an optimization, e.g. containers without $HOME still search through DescribeImages
+			fmt.Fprintf(os.Stderr, "Warning: %v, searching without the cache\n", err)
+		}
+	}
+
+	path := c.String("catalog")
+	if path == "" && imageSource.offline {
+		// The imported catalog is optional, offline searches are served from the cache alone without it
+		imported, err := importedCatalogPath(c)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(imported); err == nil {
+			path = imported
+		}
+	}
+	if path == "" {
+		return nil
+	}
+
+	b, err := loadCatalog(c, path)
+	if err != nil {
+		return err
+	}
+	if !b.Signed() {
+		fmt.Fprintf(os.Stderr, "Warning: signature of catalog %s is not checked, set catalog-public-key to make sure it comes from a trusted source\n", path)
+	}
+	imageSource.catalog = b
+	return nil
+}
+
+// loadCatalog loads the catalog at path, its signature is checked when --catalog-public-key is set
+func loadCatalog(c *cli.Command, path string) (*catalog.Bundle, error) {
+	keyPath := c.String("catalog-public-key")
+	if keyPath == "" {
+		return catalog.Load(path)
+	}
+
+	key, err := catalog.LoadPublicKey(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	return catalog.LoadSigned(path, key)
+}
+
+// trustedImageClient is newImageClient for lookups deciding whether AMIs are official,
+// anyone could edit a catalog so only signed ones are trusted.
+func trustedImageClient(ctx context.Context, region string) (ec2.DescribeImagesAPIClient, error) {
+	if imageSource.catalog != nil && !imageSource.catalog.Signed() {
+		return nil, fmt.Errorf("unsigned catalogs are not trusted to tell official AMIs, sign the catalog with \"catalog export --signing-key\" and set catalog-public-key")
+	}
+	return newImageClient(ctx, region)
+}
+
+// CatalogExport dumps every AMI matching the search flags into a catalog, deprecated ones included,
+// regardless of --max-results.
+func CatalogExport(ctx context.Context, c *cli.Command) error {
+	ctx, cancel := context.WithTimeout(ctx, c.Duration("timeout"))
+	defer cancel()
+
+	if err := applySharedFlags(c); err != nil {
+		return err
+	}
+
+	if c.Bool("recommended") {
+		return fmt.Errorf("recommended AMI lookup can not be exported, catalogs hold DescribeImages results only")
+	}
+
+	// The key is checked before any request is sent
+	var signingKey ed25519.PrivateKey
+	if keyPath := c.String("signing-key"); keyPath != "" {
+		var err error
+		if signingKey, err = catalog.LoadPrivateKey(keyPath); err != nil {
+			return err
+		}
+	}
+
+	base := amiSearchInput(c)
+	base.INCLUDE_DEPRECATED = true
+	inputs, err := expandSearchInputs(base)
+	if err != nil {
+		return err
+	}
+
+	regions := make([]string, 0, len(inputs))
+	for _, input := range inputs {
+		regions = append(regions, input.AWS_REGION)
+	}
+
+	bundle := catalog.New()
+	errs := make([]error, len(inputs))
+	forEachRegion(regions, func(idx int, region string) {
+		svc, err := newImageClient(ctx, region)
+		if err != nil {
+			errs[idx] = err
+			return
+		}
+		// Find walks through every page, the recorder keeps all of them
+		_, errs[idx] = finder.New(bundle.Record(region, svc)).Find(ctx, toFinderQuery(inputs[idx]))
+	})
+
+	var failed int
+	for idx, err := range errs {
+		if err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "Warning: [%s] %s\n", lockSearchError(inputs[idx], err).label(), err)
+		}
+	}
+	if failed == len(inputs) {
+		return fmt.Errorf("all %d searches failed", len(inputs))
+	}
+
+	path := c.String("file")
+	if err := bundle.Save(path); err != nil {
+		return err
+	}
+
+	// A signature of a previous export would no longer match
+	if err := os.Remove(catalog.SignaturePath(path)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to remove stale catalog signature: %v", err)
+	}
+	if signingKey != nil {
+		if err := catalog.Sign(path, signingKey); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Signed %s, signature written to %s\n", path, catalog.SignaturePath(path))
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d AMIs of %s to %s\n", bundle.Len(), strings.Join(bundle.Regions(), ", "), path)
+	return nil
+}
+
+// CatalogKeygen writes a new key pair to sign catalogs with, existing key files are never overwritten
+func CatalogKeygen(ctx context.Context, c *cli.Command) error {
+	publicKey, privateKey, err := catalog.GenerateKey()
+	if err != nil {
+		return err
+	}
+
+	for _, k := range []struct {
+		path string
+		data []byte
+		perm os.FileMode
+	}{
+		{c.String("private-key"), privateKey, 0o600},
+		{c.String("public-key"), publicKey, 0o644},
+	} {
+		f, err := os.OpenFile(k.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, k.perm)
+		if err != nil {
+			return fmt.Errorf("unable to write key: %v", err)
+		}
+		if _, err := f.Write(k.data); err != nil {
+			f.Close()
+			return fmt.Errorf("unable to write key: %v", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("unable to write key: %v", err)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Wrote private key to %s and public key to %s\n", c.String("private-key"), c.String("public-key"))
+	return nil
+}
+
+// CatalogImport checks a catalog, and its signature when catalog-public-key is set, then installs it
+// with its signature, if any, for offline lookups
+func CatalogImport(ctx context.Context, c *cli.Command) error {
+	if c.Args().Len() != 1 {
+		return fmt.Errorf("exactly one catalog file is required")
+	}
+	src := c.Args().First()
+
+	bundle, err := loadCatalog(c, src)
+	if err != nil {
+		return err
+	}
+
+	path, err := importedCatalogPath(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("unable to create catalog directory: %v", err)
+	}
+
+	// Files are copied as-is, the signature covers the exact bytes
+	if err := copyFile(src, path); err != nil {
+		return err
+	}
+	err = copyFile(catalog.SignaturePath(src), catalog.SignaturePath(path))
+	if errors.Is(err, os.ErrNotExist) {
+		err = os.Remove(catalog.SignaturePath(path))
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Imported %d AMIs of %s, used by --offline lookups from now on\n", bundle.Len(), strings.Join(bundle.Regions(), ", "))
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(dst, data, 0o644); err != nil {
+		return fmt.Errorf("unable to write %s: %v", dst, err)
+	}
+	return nil
+}
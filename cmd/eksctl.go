@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/guessi/eks-ami-finder/pkg/finder"
+)
+
+// Node group lists of an eksctl ClusterConfig
+// - https://eksctl.io/usage/schema/
+const (
+	eksctlManagedNodeGroups = "managedNodeGroups"
+	eksctlNodeGroups        = "nodeGroups"
+)
+
+type eksctlNodeGroup struct {
+	Name      string `yaml:"name"`
+	AMIFamily string `yaml:"amiFamily"`
+	AMI       string `yaml:"ami"`
+}
+
+type cloudFormationParameter struct {
+	ParameterKey   string `json:"ParameterKey"`
+	ParameterValue string `json:"ParameterValue"`
+}
+
+// renderEksctl writes a ClusterConfig fragment with one node group per AMI type,
+// one YAML document per region since AMI IDs are regional.
+func renderEksctl(w io.Writer, list string, results []amiSearchResult) error {
+	m, err := newestByRegionAndType(results, "eksctl")
+	if err != nil {
+		return err
+	}
+
+	for idx, region := range sortedKeys(m) {
+		var nodeGroups []eksctlNodeGroup
+		for _, amiType := range sortedKeys(m[region]) {
+			family, err := finder.EksctlAMIFamily(amiType)
+			if err != nil {
+				return err
+			}
+			nodeGroups = append(nodeGroups, eksctlNodeGroup{
+				// Node group names allow letters, digits and hyphens only
+				Name:      strings.ToLower(strings.ReplaceAll(amiType, "_", "-")),
+				AMIFamily: family,
+				AMI:       m[region][amiType].ImageId,
+			})
+		}
+
+		if idx > 0 {
+			fmt.Fprintln(w, "---")
+		}
+		fmt.Fprintf(w, "# %s\n", region)
+		if err := encodeYAML(w, map[string][]eksctlNodeGroup{list: nodeGroups}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderCloudFormation writes a parameter file for "aws cloudformation create-stack --parameters file://...",
+// which could only hold a single AMI.
+func renderCloudFormation(w io.Writer, parameter string, results []amiSearchResult) error {
+	m, err := newestByRegionAndType(results, "cloudformation")
+	if err != nil {
+		return err
+	}
+
+	regions := sortedKeys(m)
+	if len(regions) != 1 || len(m[regions[0]]) != 1 {
+		return fmt.Errorf("cloudformation output holds a single AMI, look up a single region and AMI type at a time")
+	}
+
+	amiType := sortedKeys(m[regions[0]])[0]
+	return encodeJSON(w, []cloudFormationParameter{
+		{
+			ParameterKey:   parameter,
+			ParameterValue: m[regions[0]][amiType].ImageId,
+		},
+	})
+}
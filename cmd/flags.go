@@ -4,20 +4,40 @@ import (
 	"context"
 	"fmt"
 	"slices"
-	"strconv"
 	"strings"
 	"time"
 
+	"github.com/guessi/eks-ami-finder/pkg/cache"
 	"github.com/guessi/eks-ami-finder/pkg/constants"
+	"github.com/guessi/eks-ami-finder/pkg/finder"
+	"github.com/guessi/eks-ami-finder/pkg/owners"
 	"github.com/urfave/cli/v3"
 )
 
+// applySharedFlags loads the owner mapping file and the catalog, if any, before any lookup starts
+func applySharedFlags(c *cli.Command) error {
+	if path := c.String("owner-mappings"); path != "" {
+		if err := owners.LoadOverrides(path); err != nil {
+			return err
+		}
+	}
+	return applyImageSource(c)
+}
+
+// parseOptionalTime parses a --since/--until input, empty input means unbounded
+func parseOptionalTime(v string, now time.Time, parse func(string, time.Time) (time.Time, error)) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	return parse(v, now)
+}
+
 var Flags = []cli.Flag{
 	&cli.StringFlag{
 		Name:    "region",
 		Aliases: []string{"r"},
 		Value:   "us-east-1",
-		Usage:   "Region for the AMI",
+		Usage:   "Region for the AMI, a comma-separated list or \"all\" for every commercial region",
 	},
 	&cli.StringFlag{
 		Name:    "owner-id",
@@ -29,68 +49,37 @@ var Flags = []cli.Flag{
 				return nil // Empty is allowed (will be auto-resolved)
 			}
 
-			if len(v) != 12 {
+			if !owners.IsValidOwnerId(v) {
 				return fmt.Errorf("owner-id must be a 12-digit AWS account ID")
 			}
 
-			// Check if all characters are digits
-			for _, char := range v {
-				if char < '0' || char > '9' {
-					return fmt.Errorf("owner-id must be a 12-digit AWS account ID")
-				}
-			}
-
 			return nil
 		},
 	},
+	&cli.StringFlag{
+		Name:    "owner-mappings",
+		Usage:   "Owner mapping file (YAML or JSON) merged over the built-in owner mappings",
+		Sources: cli.EnvVars("EKS_AMI_FINDER_OWNER_MAPPINGS"),
+	},
 	&cli.StringFlag{
 		Name:        "ami-type",
 		Aliases:     []string{"t"},
 		DefaultText: "\"AL2023_x86_64_STANDARD\" or \"AUTO_MODE_STANDARD_x86_64\"",
-		Usage:       "AMI Type for the AMI",
+		Usage:       "AMI Type for the AMI, accepts lists and glob-style selectors (e.g. AL2023_*, BOTTLEROCKET_*_NVIDIA*)",
 		Action: func(ctx context.Context, c *cli.Command, v string) error {
-			if v == "" {
-				return nil // Empty is allowed (will be auto-resolved based on auto-mode)
-			}
-
 			// Check context-aware validation based on auto-mode flag
-			if c.Bool("auto-mode") {
-				if !slices.Contains(constants.ValidAmiTypes["AUTO_MODE"], v) {
-					return fmt.Errorf("invalid ami-type '%s' for auto-mode. Valid types: %s", v, strings.Join(constants.ValidAmiTypes["AUTO_MODE"], ", "))
-				}
-			} else {
-				if !slices.Contains(constants.ValidAmiTypes["DEFAULT"], v) {
-					return fmt.Errorf("invalid ami-type '%s'. Supported ami-type could be found at https://docs.aws.amazon.com/eks/latest/APIReference/API_Nodegroup.html", v)
-				}
-			}
-
-			return nil
+			_, err := finder.ExpandAmiTypes(v, c.Bool("auto-mode"))
+			return err
 		},
 	},
 	&cli.StringFlag{
 		Name:    "kubernetes-version",
 		Aliases: []string{"V"},
-		Value:   "1.35",
-		Usage:   "Kubernetes version for AMI",
+		Value:   finder.LatestKubernetesVersion(time.Now()),
+		Usage:   "Kubernetes version for AMI, accepts lists (1.33,1.35), ranges (1.32-1.35), \"latest\" and \"supported\"",
 		Action: func(ctx context.Context, c *cli.Command, v string) error {
-			parts := strings.Split(v, ".")
-			if len(parts) != 2 {
-				return fmt.Errorf("invalid Kubernetes version format. Expected format: X.Y (e.g., 1.35)")
-			}
-
-			major, err1 := strconv.Atoi(parts[0])
-			minor, err2 := strconv.Atoi(parts[1])
-			if err1 != nil || err2 != nil {
-				return fmt.Errorf("invalid Kubernetes version format. Expected format: X.Y (e.g., 1.35)")
-			}
-
-			// The first Amazon EKS version was 1.10
-			// - https://aws.amazon.com/blogs/aws/amazon-eks-now-generally-available/
-			if major != 1 || minor < 10 {
-				return fmt.Errorf("the very first Amazon EKS version was 1.10, so there would have no Amazon EKS %s", v)
-			}
-
-			return nil
+			_, err := finder.ExpandKubernetesVersions(v)
+			return err
 		},
 	},
 	&cli.StringFlag{
@@ -118,6 +107,35 @@ var Flags = []cli.Flag{
 			return nil
 		},
 	},
+	&cli.StringFlag{
+		Name:  "since",
+		Usage: "Only AMIs created at or after, [yyyy-mm-dd], [yyyymmdd], RFC 3339 or relative like 30d, 4w, 12h",
+		Action: func(ctx context.Context, c *cli.Command, v string) error {
+			_, err := parseOptionalTime(v, time.Now(), finder.ParseSince)
+			return err
+		},
+	},
+	&cli.StringFlag{
+		Name:  "until",
+		Usage: "Only AMIs created before, same format as --since, a date covers the whole day",
+		Action: func(ctx context.Context, c *cli.Command, v string) error {
+			_, err := parseOptionalTime(v, time.Now(), finder.ParseUntil)
+			return err
+		},
+	},
+	&cli.StringFlag{
+		Name:  "bottlerocket-version",
+		Usage: "Bottlerocket OS release, exact (1.51.0) or constraints (\">=1.50.0 <1.52.0\")",
+		Action: func(ctx context.Context, c *cli.Command, v string) error {
+			if v == "" {
+				return nil // Empty is allowed
+			}
+			if _, err := finder.ParseVersionConstraint(v); err != nil {
+				return fmt.Errorf("invalid bottlerocket-version: %v", err)
+			}
+			return nil
+		},
+	},
 	&cli.DurationFlag{
 		Name:  "timeout",
 		Value: 30 * time.Second,
@@ -129,6 +147,52 @@ var Flags = []cli.Flag{
 			return nil
 		},
 	},
+	&cli.StringFlag{
+		Name:        "cache-dir",
+		DefaultText: "\"$XDG_CACHE_HOME/" + constants.NAME + "\" or the platform equivalent",
+		Usage:       "Directory caching DescribeImages results between runs",
+		Sources:     cli.EnvVars("EKS_AMI_FINDER_CACHE_DIR"),
+	},
+	&cli.DurationFlag{
+		Name:    "cache-ttl",
+		Value:   cache.DefaultTTL,
+		Usage:   "How long cached DescribeImages results are served, 0 disables the cache",
+		Sources: cli.EnvVars("EKS_AMI_FINDER_CACHE_TTL"),
+		Action: func(ctx context.Context, c *cli.Command, v time.Duration) error {
+			if v < 0 {
+				return fmt.Errorf("cache-ttl must not be negative")
+			}
+			return nil
+		},
+	},
+	&cli.StringFlag{
+		Name:    "catalog",
+		Usage:   "Look up AMIs in a catalog file, see \"catalog export\", instead of calling AWS",
+		Sources: cli.EnvVars("EKS_AMI_FINDER_CATALOG"),
+		Action: func(ctx context.Context, c *cli.Command, v string) error {
+			if v != "" && c.Bool("recommended") {
+				return fmt.Errorf("recommended AMI lookup requires SSM access and can not be combined with catalog")
+			}
+			return nil
+		},
+	},
+	&cli.StringFlag{
+		Name:    "catalog-public-key",
+		Usage:   "Public key catalogs must be signed with, see \"catalog keygen\". inspect and verify only trust signed catalogs",
+		Sources: cli.EnvVars("EKS_AMI_FINDER_CATALOG_PUBLIC_KEY"),
+	},
+	&cli.BoolFlag{
+		Name:    "offline",
+		Value:   false,
+		Usage:   "Serve lookups from the cache and the imported catalog only regardless of cache-ttl, without calling AWS",
+		Sources: cli.EnvVars("EKS_AMI_FINDER_OFFLINE"),
+		Action: func(ctx context.Context, c *cli.Command, v bool) error {
+			if v && c.Bool("recommended") {
+				return fmt.Errorf("recommended AMI lookup requires SSM access and can not be combined with offline")
+			}
+			return nil
+		},
+	},
 	&cli.BoolFlag{
 		Name:  "auto-mode",
 		Value: false,
@@ -137,10 +201,15 @@ var Flags = []cli.Flag{
 		Name:  "include-deprecated",
 		Value: false,
 	},
+	&cli.BoolFlag{
+		Name:  "recommended",
+		Value: false,
+		Usage: "Look up the AMI currently recommended by AWS through SSM public parameters",
+	},
 	&cli.IntFlag{
 		Name:    "max-results",
 		Aliases: []string{"n"},
-		Value:   20,
+		Value:   finder.DefaultMaxResults,
 		Action: func(ctx context.Context, c *cli.Command, v int) error {
 			if v <= 0 {
 				return fmt.Errorf("max-results must be greater than 0")
@@ -148,6 +217,81 @@ var Flags = []cli.Flag{
 			return nil
 		},
 	},
+	&cli.StringFlag{
+		Name:  "output",
+		Value: constants.OutputFormatTable,
+		Usage: "Output format, one of: " + strings.Join(constants.ValidOutputFormats, ", "),
+		Action: func(ctx context.Context, c *cli.Command, v string) error {
+			if !slices.Contains(constants.ValidOutputFormats, v) {
+				return fmt.Errorf("invalid output format '%s'. Valid formats: %s", v, strings.Join(constants.ValidOutputFormats, ", "))
+			}
+			return nil
+		},
+	},
+	&cli.StringFlag{
+		Name:  "terraform-variable",
+		Value: "eks_ami_ids",
+		Usage: "Variable name used by the tfvars and hcl output formats",
+		Action: func(ctx context.Context, c *cli.Command, v string) error {
+			if !terraformIdentifierRegex.MatchString(v) {
+				return fmt.Errorf("invalid terraform-variable '%s', expected a valid Terraform identifier", v)
+			}
+			return nil
+		},
+	},
+	&cli.BoolFlag{
+		Name:  "terraform-data-source",
+		Usage: "Also emit an aws_ami data block per region and AMI type with the owner and name filter used, hcl output only",
+		Action: func(ctx context.Context, c *cli.Command, v bool) error {
+			if v && c.String("output") != constants.OutputFormatHCL {
+				return fmt.Errorf("terraform-data-source requires --output %s", constants.OutputFormatHCL)
+			}
+			return nil
+		},
+	},
+	&cli.StringFlag{
+		Name:  "eksctl-node-groups",
+		Value: eksctlManagedNodeGroups,
+		Usage: "Node group list used by the eksctl output format, one of: " + eksctlManagedNodeGroups + ", " + eksctlNodeGroups,
+		Action: func(ctx context.Context, c *cli.Command, v string) error {
+			if v != eksctlManagedNodeGroups && v != eksctlNodeGroups {
+				return fmt.Errorf("invalid eksctl-node-groups '%s'. Valid values: %s, %s", v, eksctlManagedNodeGroups, eksctlNodeGroups)
+			}
+			return nil
+		},
+	},
+	&cli.StringFlag{
+		Name:  "cloudformation-parameter",
+		Value: "NodeImageId",
+		Usage: "Parameter holding the AMI ID in the cloudformation output format",
+	},
+	&cli.StringFlag{
+		Name:  "template",
+		Usage: "Go text/template rendered for each result instead of --output, e.g. '{{.ImageId}} {{.Name}}'",
+		Action: func(ctx context.Context, c *cli.Command, v string) error {
+			_, err := newOutputTemplate(v, c.String("template-file"), time.Now())
+			return err
+		},
+	},
+	&cli.StringFlag{
+		Name:  "template-file",
+		Usage: "Read the Go text/template from the given file, see --template",
+		Action: func(ctx context.Context, c *cli.Command, v string) error {
+			_, err := newOutputTemplate(c.String("template"), v, time.Now())
+			return err
+		},
+	},
+	&cli.StringFlag{
+		Name:  "template-scope",
+		Value: templateScopeResult,
+		Usage: "Execute the template for each \"" + templateScopeResult + "\", or once for \"" + templateScopeAll + "\" results (.Results, .Errors)",
+		Action: func(ctx context.Context, c *cli.Command, v string) error {
+			if v != templateScopeResult && v != templateScopeAll {
+				return fmt.Errorf("invalid template-scope '%s'. Valid values: %s, %s", v, templateScopeResult, templateScopeAll)
+			}
+			return nil
+		},
+	},
 	&cli.BoolFlag{
 		Name:  "debug",
 		Value: false,
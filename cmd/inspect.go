@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+	"github.com/guessi/eks-ami-finder/pkg/finder"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/urfave/cli/v3"
+)
+
+func imageIdsFromArgs(c *cli.Command) ([]string, error) {
+	imageIds := c.Args().Slice()
+	if len(imageIds) == 0 {
+		return nil, fmt.Errorf("at least one AMI ID is required")
+	}
+
+	for _, id := range imageIds {
+		if !strings.HasPrefix(id, "ami-") {
+			return nil, fmt.Errorf("invalid AMI ID '%s'. Expected format: ami-xxxxxxxxxxxxxxxxx", id)
+		}
+	}
+
+	return imageIds, nil
+}
+
+// amiInspect looks the AMIs up in every region, AMI IDs are regional so each one is found in one region at most
+func amiInspect(ctx context.Context, regions, imageIds []string) ([]amiInspectResult, error) {
+	inspections := make([][]finder.Inspection, len(regions))
+	errs := make([]error, len(regions))
+	forEachRegion(regions, func(idx int, region string) {
+		svc, err := trustedImageClient(ctx, region)
+		if err != nil {
+			errs[idx] = err
+			return
+		}
+		inspections[idx], errs[idx] = finder.New(svc).Inspect(ctx, region, imageIds)
+	})
+
+	var results []amiInspectResult
+	for idx, region := range regions {
+		if errs[idx] != nil {
+			return nil, fmt.Errorf("[%s] %v", region, errs[idx])
+		}
+		results = append(results, inspectResults(inspections[idx])...)
+	}
+
+	return results, nil
+}
+
+func inspectResults(inspections []finder.Inspection) []amiInspectResult {
+	results := make([]amiInspectResult, 0, len(inspections))
+	for _, i := range inspections {
+		results = append(results, amiInspectResult{
+			Region:            i.Region,
+			ImageId:           i.Image.ImageId,
+			Name:              i.Image.Name,
+			OwnerId:           i.Image.OwnerId,
+			AmiType:           i.Identity.AmiType,
+			KubernetesVersion: i.Identity.KubernetesVersion,
+			ReleaseVersion:    i.Identity.ReleaseVersion,
+			CreationDate:      formatTime(i.Image.CreationDate),
+			DeprecationTime:   formatTime(i.Image.DeprecationTime),
+			Identified:        i.Identified,
+			Official:          i.Official,
+		})
+	}
+	return results
+}
+
+func renderInspectResults(w io.Writer, format string, results []amiInspectResult) error {
+	if results == nil {
+		results = []amiInspectResult{}
+	}
+	output := amiInspectOutput{
+		SchemaVersion: outputSchemaVersion,
+		Results:       results,
+	}
+
+	switch format {
+	case "", constants.OutputFormatTable:
+		t := table.NewWriter()
+		t.SetOutputMirror(w)
+		t.AppendHeader(table.Row{
+			"Region",
+			"AMI ID",
+			"AMI Type",
+			"Kubernetes Version",
+			"Release Version",
+			"Creation Date",
+			"Owner ID",
+			"Official",
+		})
+		for _, r := range results {
+			amiType := r.AmiType
+			if !r.Identified {
+				amiType = "(unknown)"
+			}
+			t.AppendRow(table.Row{
+				r.Region,
+				r.ImageId,
+				amiType,
+				r.KubernetesVersion,
+				r.ReleaseVersion,
+				r.CreationDate,
+				r.OwnerId,
+				r.Official,
+			})
+		}
+		t.Style().Format.Header = text.FormatDefault
+		t.Render()
+		return nil
+	case constants.OutputFormatJSON:
+		return encodeJSON(w, output)
+	case constants.OutputFormatYAML:
+		return encodeYAML(w, output)
+	case constants.OutputFormatCSV:
+		rows := make([][]string, 0, len(results))
+		for _, r := range results {
+			rows = append(rows, []string{
+				r.Region,
+				r.ImageId,
+				r.Name,
+				r.OwnerId,
+				r.AmiType,
+				r.KubernetesVersion,
+				r.ReleaseVersion,
+				r.CreationDate,
+				r.DeprecationTime,
+				strconv.FormatBool(r.Identified),
+				strconv.FormatBool(r.Official),
+			})
+		}
+		return writeCSV(w, []string{
+			"region",
+			"imageId",
+			"name",
+			"ownerId",
+			"amiType",
+			"kubernetesVersion",
+			"releaseVersion",
+			"creationDate",
+			"deprecationTime",
+			"identified",
+			"official",
+		}, rows)
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+func Inspect(ctx context.Context, c *cli.Command) error {
+	ctx, cancel := context.WithTimeout(ctx, c.Duration("timeout"))
+	defer cancel()
+
+	if err := applySharedFlags(c); err != nil {
+		return err
+	}
+
+	imageIds, err := imageIdsFromArgs(c)
+	if err != nil {
+		return err
+	}
+
+	regions, err := parseRegions(c.String("region"))
+	if err != nil {
+		return err
+	}
+
+	results, err := amiInspect(ctx, regions, imageIds)
+	if err != nil {
+		return err
+	}
+
+	if err := renderInspectResults(os.Stdout, c.String("output"), results); err != nil {
+		return fmt.Errorf("unable to render results: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/guessi/eks-ami-finder/pkg/finder"
+	"github.com/urfave/cli/v3"
+)
+
+type karpenterSelectorTerm struct {
+	Alias string `yaml:"alias,omitempty"`
+	ID    string `yaml:"id,omitempty"`
+}
+
+// karpenterNodeClassSpec is the AMI related part of an EC2NodeClass spec
+// - https://karpenter.sh/docs/concepts/nodeclasses/
+type karpenterNodeClassSpec struct {
+	AMIFamily        string                  `yaml:"amiFamily,omitempty"`
+	AMISelectorTerms []karpenterSelectorTerm `yaml:"amiSelectorTerms"`
+}
+
+// renderKarpenter writes an amiSelectorTerms fragment selecting results by ID, one YAML document
+// per region and amiFamily since AMI IDs are regional.
+func renderKarpenter(w io.Writer, results []amiSearchResult) error {
+	if len(results) == 0 {
+		return fmt.Errorf("no matching AMI found")
+	}
+
+	type group struct {
+		region string
+		spec   karpenterNodeClassSpec
+	}
+	var groups []*group
+	for _, r := range results {
+		family, err := finder.KarpenterAMIFamily(r.AmiType)
+		if err != nil {
+			return err
+		}
+
+		var g *group
+		for _, candidate := range groups {
+			if candidate.region == r.Region && candidate.spec.AMIFamily == family {
+				g = candidate
+			}
+		}
+		if g == nil {
+			g = &group{region: r.Region, spec: karpenterNodeClassSpec{AMIFamily: family}}
+			groups = append(groups, g)
+		}
+		g.spec.AMISelectorTerms = append(g.spec.AMISelectorTerms, karpenterSelectorTerm{ID: r.ImageId})
+	}
+
+	for idx, g := range groups {
+		if idx > 0 {
+			fmt.Fprintln(w, "---")
+		}
+		fmt.Fprintf(w, "# %s\n", g.region)
+		if err := encodeYAML(w, g.spec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderKarpenterAliases writes the distinct aliases pinning the releases of results, one per line.
+// Aliases are not regional, so the same release found in many regions is printed once.
+func renderKarpenterAliases(w io.Writer, results []amiSearchResult) error {
+	var aliases []string
+	var errs []string
+	for _, r := range results {
+		m, _ := finder.ParseMetadata(r.Name, r.Description)
+		alias, err := finder.KarpenterAlias(m)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", r.ImageId, err))
+			continue
+		}
+		if !slices.Contains(aliases, alias) {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	if len(aliases) == 0 {
+		if len(errs) > 0 {
+			return fmt.Errorf("%s", errs[0])
+		}
+		return fmt.Errorf("no matching AMI found")
+	}
+
+	for _, alias := range aliases {
+		fmt.Fprintln(w, alias)
+	}
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", e)
+	}
+
+	return nil
+}
+
+// KarpenterResolve looks up the concrete AMI IDs a Karpenter alias selects, for each region
+// and each variant of the family.
+func KarpenterResolve(ctx context.Context, c *cli.Command) error {
+	ctx, cancel := context.WithTimeout(ctx, c.Duration("timeout"))
+	defer cancel()
+
+	if err := applySharedFlags(c); err != nil {
+		return err
+	}
+
+	if c.Args().Len() != 1 {
+		return fmt.Errorf("exactly one alias is required, e.g. al2023@v20260120")
+	}
+
+	q, err := finder.ParseKarpenterAlias(c.Args().First())
+	if err != nil {
+		return err
+	}
+
+	base := amiSearchInput(c)
+	base.AMI_TYPE = strings.Join(q.AmiTypes, ",")
+	base.AUTO_MODE = false
+	base.RELEASE_DATE = q.ReleaseDate
+	base.BOTTLEROCKET_VERSION = q.BottlerocketVersion
+	base.MAX_RESULTS = 1
+
+	return searchAndRender(ctx, base)
+}
@@ -0,0 +1,423 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+	"github.com/guessi/eks-ami-finder/pkg/finder"
+	"github.com/guessi/eks-ami-finder/pkg/lockfile"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/urfave/cli/v3"
+)
+
+var LockFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "lock-file",
+		Value: lockfile.DefaultPath,
+		Usage: "Path of the lockfile",
+	},
+}
+
+// lockEntryFromResult pins the search result together with the query it was found with
+func lockEntryFromResult(input amiSearchInputSpec, r amiSearchResult) lockfile.Entry {
+	return lockfile.Entry{
+		Region:              input.AWS_REGION,
+		AmiType:             input.AMI_TYPE,
+		AutoMode:            input.AUTO_MODE,
+		KubernetesVersion:   input.KUBERNETES_VERSION,
+		ReleaseDate:         input.RELEASE_DATE,
+		BottlerocketVersion: input.BOTTLEROCKET_VERSION,
+		Recommended:         input.RECOMMENDED,
+		QueryOwnerId:        input.AMI_OWNER_ID,
+		IncludeDeprecated:   input.INCLUDE_DEPRECATED,
+		ImageId:             r.ImageId,
+		Name:                r.Name,
+		OwnerId:             r.OwnerId,
+		CreationDate:        r.CreationDate,
+	}
+}
+
+// lockEntryInput turns a locked entry back into the query it was resolved from,
+// relative --since/--until are resolved against now.
+func lockEntryInput(e lockfile.Entry, now time.Time, debug bool) (amiSearchInputSpec, error) {
+	since, err := parseOptionalTime(e.Since, now, finder.ParseSince)
+	if err != nil {
+		return amiSearchInputSpec{}, fmt.Errorf("invalid since of %s: %v", e.Key(), err)
+	}
+	until, err := parseOptionalTime(e.Until, now, finder.ParseUntil)
+	if err != nil {
+		return amiSearchInputSpec{}, fmt.Errorf("invalid until of %s: %v", e.Key(), err)
+	}
+
+	return amiSearchInputSpec{
+		AWS_REGION:           e.Region,
+		AMI_OWNER_ID:         e.QueryOwnerId,
+		AMI_TYPE:             e.AmiType,
+		KUBERNETES_VERSION:   e.KubernetesVersion,
+		RELEASE_DATE:         e.ReleaseDate,
+		SINCE:                since,
+		UNTIL:                until,
+		BOTTLEROCKET_VERSION: e.BottlerocketVersion,
+		MAX_RESULTS:          1,
+		AUTO_MODE:            e.AutoMode,
+		INCLUDE_DEPRECATED:   e.IncludeDeprecated,
+		RECOMMENDED:          e.Recommended,
+		DEBUG_MODE:           debug,
+	}, nil
+}
+
+// loadOrCreateLockfile returns an empty lockfile when there is none yet
+func loadOrCreateLockfile(path string) (*lockfile.File, error) {
+	f, err := lockfile.Load(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return lockfile.New(), nil
+	}
+	return f, err
+}
+
+// newestForEach looks up the newest AMI of each input, errs[idx] is set when inputs[idx] found nothing
+func newestForEach(ctx context.Context, inputs []amiSearchInputSpec) (newest []amiSearchResult, errs []error) {
+	newest = make([]amiSearchResult, len(inputs))
+	errs = make([]error, len(inputs))
+	for idx, o := range multiRegionSearch(ctx, inputs) {
+		switch {
+		case o.Err != nil:
+			errs[idx] = o.Err
+		case len(o.Results) == 0:
+			errs[idx] = fmt.Errorf("no matching AMI found")
+		default:
+			newest[idx] = o.Results[0]
+		}
+	}
+	return newest, errs
+}
+
+// lockSearchError labels a failed lookup with region, AMI type and Kubernetes version
+func lockSearchError(input amiSearchInputSpec, err error) amiSearchError {
+	return amiSearchError{
+		Region:            input.AWS_REGION,
+		AmiType:           input.AMI_TYPE,
+		KubernetesVersion: input.KUBERNETES_VERSION,
+		Error:             err.Error(),
+	}
+}
+
+func renderLockEntries(w io.Writer, format string, entries []lockfile.Entry) error {
+	if entries == nil {
+		entries = []lockfile.Entry{}
+	}
+	output := lockfile.File{
+		SchemaVersion: lockfile.SchemaVersion,
+		Entries:       entries,
+	}
+
+	switch format {
+	case "", constants.OutputFormatTable:
+		t := table.NewWriter()
+		t.SetOutputMirror(w)
+		t.AppendHeader(table.Row{
+			"Region",
+			"AMI Type",
+			"Kubernetes Version",
+			"AMI ID",
+			"Name",
+			"Owner ID",
+		})
+		for _, e := range entries {
+			t.AppendRow(table.Row{
+				e.Region,
+				e.AmiType,
+				e.KubernetesVersion,
+				e.ImageId,
+				e.Name,
+				e.OwnerId,
+			})
+		}
+		t.Style().Format.Header = text.FormatDefault
+		t.Render()
+		return nil
+	case constants.OutputFormatJSON:
+		return encodeJSON(w, output)
+	case constants.OutputFormatYAML:
+		return encodeYAML(w, output)
+	case constants.OutputFormatCSV:
+		rows := make([][]string, 0, len(entries))
+		for _, e := range entries {
+			rows = append(rows, []string{
+				e.Region,
+				e.AmiType,
+				strconv.FormatBool(e.AutoMode),
+				e.KubernetesVersion,
+				e.ImageId,
+				e.Name,
+				e.OwnerId,
+				e.CreationDate,
+			})
+		}
+		return writeCSV(w, []string{
+			"region",
+			"amiType",
+			"autoMode",
+			"kubernetesVersion",
+			"imageId",
+			"name",
+			"ownerId",
+			"creationDate",
+		}, rows)
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// Lock pins the newest AMI of each region, AMI type and Kubernetes version into the lockfile,
+// entries of other slots already in the lockfile are kept.
+func Lock(ctx context.Context, c *cli.Command) error {
+	ctx, cancel := context.WithTimeout(ctx, c.Duration("timeout"))
+	defer cancel()
+
+	if err := applySharedFlags(c); err != nil {
+		return err
+	}
+
+	path := c.String("lock-file")
+	f, err := loadOrCreateLockfile(path)
+	if err != nil {
+		return err
+	}
+
+	base := amiSearchInput(c)
+	base.MAX_RESULTS = 1
+
+	inputs, err := expandSearchInputs(base)
+	if err != nil {
+		return err
+	}
+
+	newest, errs := newestForEach(ctx, inputs)
+	failed := 0
+	for idx, err := range errs {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[%s] %s\n", lockSearchError(inputs[idx], err).label(), err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("unable to lock %d of %d AMI(s), lockfile is left unchanged", failed, len(inputs))
+	}
+
+	entries := make([]lockfile.Entry, 0, len(inputs))
+	for idx, input := range inputs {
+		e := lockEntryFromResult(input, newest[idx])
+		// Kept as given rather than resolved, so that relative bounds keep moving on updates
+		e.Since, e.Until = c.String("since"), c.String("until")
+		f.Upsert(e)
+		entries = append(entries, e)
+	}
+
+	if err := f.Save(path); err != nil {
+		return err
+	}
+
+	if err := renderLockEntries(os.Stdout, c.String("output"), entries); err != nil {
+		return fmt.Errorf("unable to render results: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "Locked %d AMI(s) in %s\n", len(entries), path)
+
+	return nil
+}
+
+// verifyLockEntries checks every entry still exists, is published by the official owner,
+// matches what was locked and is not deprecated.
+func verifyLockEntries(ctx context.Context, entries []lockfile.Entry, now time.Time) []amiVerifyResult {
+	var regions []string
+	for _, e := range entries {
+		if !slices.Contains(regions, e.Region) {
+			regions = append(regions, e.Region)
+		}
+	}
+
+	verifications := make([]map[string]finder.Verification, len(regions))
+	regionErrs := make([]error, len(regions))
+	forEachRegion(regions, func(idx int, region string) {
+		var imageIds []string
+		for _, e := range entries {
+			if e.Region == region && !slices.Contains(imageIds, e.ImageId) {
+				imageIds = append(imageIds, e.ImageId)
+			}
+		}
+
+		svc, err := trustedImageClient(ctx, region)
+		if err != nil {
+			regionErrs[idx] = err
+			return
+		}
+
+		results, err := finder.New(svc).Verify(ctx, region, imageIds)
+		if err != nil {
+			regionErrs[idx] = err
+			return
+		}
+
+		verifications[idx] = make(map[string]finder.Verification, len(results))
+		for _, v := range results {
+			verifications[idx][v.ImageId] = v
+		}
+	})
+
+	results := make([]amiVerifyResult, 0, len(entries))
+	for _, e := range entries {
+		r := amiVerifyResult{
+			Region:   e.Region,
+			ImageId:  e.ImageId,
+			Name:     e.Name,
+			AmiType:  e.AmiType,
+			OwnerId:  e.OwnerId,
+			Problems: []string{},
+			Status:   finder.VerificationFailed,
+		}
+
+		idx := slices.Index(regions, e.Region)
+		if err := regionErrs[idx]; err != nil {
+			r.Problems = append(r.Problems, err.Error())
+			results = append(results, r)
+			continue
+		}
+
+		v := verifications[idx][e.ImageId]
+		r.Problems = append(r.Problems, v.Problems...)
+		r.ExpectedOwnerId = v.ExpectedOwnerId
+		if v.Found {
+			image := v.Inspection.Image
+			r.OwnerId = image.OwnerId
+			if image.Name != e.Name {
+				r.Problems = append(r.Problems, fmt.Sprintf("name %s does not match locked name %s", image.Name, e.Name))
+			}
+			if image.OwnerId != e.OwnerId {
+				r.Problems = append(r.Problems, fmt.Sprintf("owner %s does not match locked owner %s", image.OwnerId, e.OwnerId))
+			}
+			if id := v.Inspection.Identity; v.Inspection.Identified && (id.AmiType != e.AmiType || id.KubernetesVersion != e.KubernetesVersion) {
+				r.Problems = append(r.Problems, fmt.Sprintf("AMI is %s for Kubernetes %s, locked as %s for Kubernetes %s", id.AmiType, id.KubernetesVersion, e.AmiType, e.KubernetesVersion))
+			}
+			if !image.DeprecationTime.IsZero() && !image.DeprecationTime.After(now) {
+				r.Problems = append(r.Problems, fmt.Sprintf("deprecated since %s", formatTime(image.DeprecationTime)))
+			}
+		}
+
+		r.Passed = len(r.Problems) == 0
+		switch {
+		case r.Passed:
+			r.Status = finder.VerificationPassed
+		case v.Status() == finder.VerificationOverride && len(r.Problems) == len(v.Problems):
+			// Nothing else differs from what was locked
+			r.Status = finder.VerificationOverride
+			r.OverrideSource = v.OverrideSource
+		}
+		results = append(results, r)
+	}
+
+	return results
+}
+
+func LockVerify(ctx context.Context, c *cli.Command) error {
+	ctx, cancel := context.WithTimeout(ctx, c.Duration("timeout"))
+	defer cancel()
+
+	if err := applySharedFlags(c); err != nil {
+		return err
+	}
+
+	f, err := lockfile.Load(c.String("lock-file"))
+	if err != nil {
+		return err
+	}
+
+	results := verifyLockEntries(ctx, f.Entries, time.Now())
+
+	if err := renderVerifyResults(os.Stdout, c.String("output"), results); err != nil {
+		return fmt.Errorf("unable to render results: %v", err)
+	}
+
+	failed := 0
+	for _, r := range results {
+		if !r.Passed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("verification failed for %d of %d locked AMI(s)", failed, len(results))
+	}
+
+	return nil
+}
+
+// LockUpdate bumps every entry to the newest AMI matching the query it was locked with, and prints a diff
+func LockUpdate(ctx context.Context, c *cli.Command) error {
+	ctx, cancel := context.WithTimeout(ctx, c.Duration("timeout"))
+	defer cancel()
+
+	if err := applySharedFlags(c); err != nil {
+		return err
+	}
+
+	path := c.String("lock-file")
+	f, err := lockfile.Load(path)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	inputs := make([]amiSearchInputSpec, 0, len(f.Entries))
+	for _, e := range f.Entries {
+		input, err := lockEntryInput(e, now, c.Bool("debug"))
+		if err != nil {
+			return fmt.Errorf("%v in %s", err, path)
+		}
+		inputs = append(inputs, input)
+	}
+
+	newest, errs := newestForEach(ctx, inputs)
+
+	updated := 0
+	for idx, e := range f.Entries {
+		if errs[idx] != nil || newest[idx].ImageId == e.ImageId {
+			continue
+		}
+
+		next := lockEntryFromResult(inputs[idx], newest[idx])
+		next.Since, next.Until = e.Since, e.Until
+		fmt.Fprintf(os.Stdout, "%s\n- %s %s\n+ %s %s\n", e.Key(), e.ImageId, e.Name, next.ImageId, next.Name)
+		f.Entries[idx] = next
+		updated++
+	}
+
+	failed := 0
+	for idx, err := range errs {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: [%s] %s\n", lockSearchError(inputs[idx], err).label(), err)
+			failed++
+		}
+	}
+
+	if updated > 0 {
+		if err := f.Save(path); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Updated %d of %d entries in %s\n", updated, len(f.Entries), path)
+	} else if failed == 0 {
+		fmt.Fprintf(os.Stderr, "All %d entries in %s are up to date\n", len(f.Entries), path)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("unable to update %d of %d entries", failed, len(f.Entries))
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+	"github.com/guessi/eks-ami-finder/pkg/finder"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/urfave/cli/v3"
+)
+
+// compatibilityMatrix lists the supported versions of each AMI type among the given Kubernetes versions
+func compatibilityMatrix(amiTypes []string, autoMode bool, versions []string) []compatibilityRow {
+	rows := make([]compatibilityRow, 0, len(amiTypes))
+	for _, amiType := range amiTypes {
+		compat := finder.CompatibilityOf(amiType, autoMode)
+		row := compatibilityRow{
+			AmiType:              amiType,
+			AutoMode:             autoMode,
+			MinKubernetesVersion: compat.MinVersion,
+			MaxKubernetesVersion: compat.MaxVersion,
+			SupportedVersions:    []string{},
+			Sources:              compat.Sources,
+		}
+		if row.Sources == nil {
+			row.Sources = []string{}
+		}
+		for _, v := range versions {
+			if compat.Supports(v) {
+				row.SupportedVersions = append(row.SupportedVersions, v)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func renderCompatibilityMatrix(w io.Writer, format string, versions []string, rows []compatibilityRow) error {
+	switch format {
+	case "", constants.OutputFormatTable:
+		t := table.NewWriter()
+		t.SetOutputMirror(w)
+		header := table.Row{"AMI Type"}
+		for _, v := range versions {
+			header = append(header, v)
+		}
+		t.AppendHeader(header)
+		for _, r := range rows {
+			row := table.Row{r.AmiType}
+			for _, v := range versions {
+				if slices.Contains(r.SupportedVersions, v) {
+					row = append(row, "✓")
+				} else {
+					row = append(row, "-")
+				}
+			}
+			t.AppendRow(row)
+		}
+		t.Style().Format.Header = text.FormatDefault
+		t.Render()
+		return nil
+	case constants.OutputFormatJSON:
+		return encodeJSON(w, compatibilityOutput{SchemaVersion: outputSchemaVersion, KubernetesVersions: versions, Results: rows})
+	case constants.OutputFormatYAML:
+		return encodeYAML(w, compatibilityOutput{SchemaVersion: outputSchemaVersion, KubernetesVersions: versions, Results: rows})
+	case constants.OutputFormatCSV:
+		header := []string{
+			"amiType",
+			"autoMode",
+			"minKubernetesVersion",
+			"maxKubernetesVersion",
+		}
+		header = append(header, versions...)
+		records := make([][]string, 0, len(rows))
+		for _, r := range rows {
+			record := []string{
+				r.AmiType,
+				strconv.FormatBool(r.AutoMode),
+				r.MinKubernetesVersion,
+				r.MaxKubernetesVersion,
+			}
+			for _, v := range versions {
+				record = append(record, strconv.FormatBool(slices.Contains(r.SupportedVersions, v)))
+			}
+			records = append(records, record)
+		}
+		return writeCSV(w, header, records)
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// matrixVersions spans every version of the lifecycle table up to the newest one released, stretched back
+// to the oldest version a compatibility rule refers to so the bounds of every rule show up in the grid.
+func matrixVersions(now time.Time) ([]string, error) {
+	oldest := constants.KubernetesReleases[0].Version
+	for _, r := range finder.CompatibilityRules {
+		for _, v := range []string{r.MinVersion, r.MaxVersion} {
+			if v != "" && finder.CompareKubernetesVersions(v, oldest) < 0 {
+				oldest = v
+			}
+		}
+	}
+	return finder.ExpandKubernetesVersions(oldest + "-" + finder.LatestKubernetesVersion(now))
+}
+
+// Matrix prints which Kubernetes versions each AMI type is published for. Every AMI type is listed against
+// every Amazon EKS version unless --ami-type, --auto-mode or --kubernetes-version is given.
+func Matrix(ctx context.Context, c *cli.Command) error {
+	var versions []string
+	var err error
+	if c.IsSet("kubernetes-version") {
+		versions, err = finder.ExpandKubernetesVersions(c.String("kubernetes-version"))
+	} else {
+		versions, err = matrixVersions(time.Now())
+	}
+	if err != nil {
+		return err
+	}
+
+	var rows []compatibilityRow
+	if c.IsSet("ami-type") || c.Bool("auto-mode") {
+		amiTypes, err := finder.ExpandAmiTypes(c.String("ami-type"), c.Bool("auto-mode"))
+		if err != nil {
+			return err
+		}
+		rows = compatibilityMatrix(amiTypes, c.Bool("auto-mode"), versions)
+	} else {
+		rows = append(compatibilityMatrix(finder.ValidAmiTypes(false), false, versions),
+			compatibilityMatrix(finder.ValidAmiTypes(true), true, versions)...)
+	}
+
+	if err := renderCompatibilityMatrix(os.Stdout, c.String("output"), versions, rows); err != nil {
+		return fmt.Errorf("unable to render results: %v", err)
+	}
+	return nil
+}
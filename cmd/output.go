@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+	"github.com/guessi/eks-ami-finder/pkg/finder"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"go.yaml.in/yaml/v3"
+)
+
+// Schema version of structured (json/yaml) output, bump it on any breaking change
+const outputSchemaVersion = "v1"
+
+// sortResults groups results by AMI type then Kubernetes version, newest version and newest AMI first
+func sortResults(results []amiSearchResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].AmiType != results[j].AmiType {
+			return results[i].AmiType < results[j].AmiType
+		}
+		if c := finder.CompareKubernetesVersions(results[i].KubernetesVersion, results[j].KubernetesVersion); c != 0 {
+			return c > 0
+		}
+		// Bottlerocket releases are ordered by semantic version, not by string or creation date
+		if vi, err := finder.ParseSemver(results[i].BottlerocketVersion); err == nil {
+			if vj, err := finder.ParseSemver(results[j].BottlerocketVersion); err == nil && vi.Compare(vj) != 0 {
+				return vi.Compare(vj) > 0
+			}
+		}
+		// CreationDate is ISO 8601, so string comparison is good enough here
+		return results[i].CreationDate > results[j].CreationDate
+	})
+}
+
+func renderResults(w io.Writer, input amiSearchInputSpec, results []amiSearchResult, errs []amiSearchError) error {
+	sortResults(results)
+
+	// Templates take over the output format
+	if input.TEMPLATE != "" || input.TEMPLATE_FILE != "" {
+		return renderTemplate(w, input, results, errs)
+	}
+
+	switch format := input.OUTPUT_FORMAT; format {
+	case "", constants.OutputFormatTable:
+		return renderTable(w, results)
+	case constants.OutputFormatJSON:
+		return encodeJSON(w, newSearchOutput(results, errs))
+	case constants.OutputFormatYAML:
+		return encodeYAML(w, newSearchOutput(results, errs))
+	case constants.OutputFormatCSV:
+		return renderCSV(w, results)
+	case constants.OutputFormatKarpenter:
+		return renderKarpenter(w, results)
+	case constants.OutputFormatKarpenterAlias:
+		return renderKarpenterAliases(w, results)
+	case constants.OutputFormatTfvars:
+		return renderTfvars(w, input.TERRAFORM_VARIABLE, results)
+	case constants.OutputFormatHCL:
+		return renderHCL(w, input, results)
+	case constants.OutputFormatEksctl:
+		return renderEksctl(w, input.EKSCTL_NODE_GROUPS, results)
+	case constants.OutputFormatCloudFormation:
+		return renderCloudFormation(w, input.CLOUDFORMATION_PARAMETER, results)
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+func renderTable(w io.Writer, results []amiSearchResult) error {
+	if len(results) == 0 {
+		fmt.Fprintf(w, "No matching AMI found.\n\n")
+		return nil
+	}
+
+	// Only show the recommended column when looking up recommended AMIs
+	recommended := slices.ContainsFunc(results, func(r amiSearchResult) bool { return r.Recommended })
+	// Only show the type and version columns when results span multiple of them
+	multiType := slices.ContainsFunc(results, func(r amiSearchResult) bool { return r.AmiType != results[0].AmiType })
+	multiVersion := slices.ContainsFunc(results, func(r amiSearchResult) bool { return r.KubernetesVersion != results[0].KubernetesVersion })
+	multiOS := slices.ContainsFunc(results, func(r amiSearchResult) bool { return r.OSFamily != results[0].OSFamily })
+
+	// Metadata columns are only shown when at least one result carries them
+	type column struct {
+		header string
+		value  func(r amiSearchResult) any
+	}
+	var metadataColumns []column
+	addColumn := func(show bool, header string, value func(r amiSearchResult) any) {
+		if show {
+			metadataColumns = append(metadataColumns, column{header, value})
+		}
+	}
+	addColumn(multiOS, "OS", func(r amiSearchResult) any { return r.OSFamily })
+	addColumn(slices.ContainsFunc(results, func(r amiSearchResult) bool { return r.KubernetesPatchVersion != "" }),
+		"Kubernetes Patch", func(r amiSearchResult) any { return r.KubernetesPatchVersion })
+	addColumn(slices.ContainsFunc(results, func(r amiSearchResult) bool { return r.ReleaseVersion != "" }),
+		"Release", func(r amiSearchResult) any { return r.ReleaseVersion })
+	addColumn(slices.ContainsFunc(results, func(r amiSearchResult) bool { return r.ContainerdVersion != "" }),
+		"Containerd", func(r amiSearchResult) any { return r.ContainerdVersion })
+	addColumn(slices.ContainsFunc(results, func(r amiSearchResult) bool { return r.Accelerator != "" }),
+		"Accelerator", func(r amiSearchResult) any { return r.Accelerator })
+	addColumn(slices.ContainsFunc(results, func(r amiSearchResult) bool { return r.FIPS }),
+		"FIPS", func(r amiSearchResult) any { return r.FIPS })
+
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	header := table.Row{"Region"}
+	if multiType {
+		header = append(header, "AMI Type")
+	}
+	if multiVersion {
+		header = append(header, "Kubernetes Version")
+	}
+	header = append(header,
+		"AMI ID",
+		"Name",
+		"Description",
+		"DeprecationTime",
+		"Architecture",
+	)
+	for _, c := range metadataColumns {
+		header = append(header, c.header)
+	}
+	if recommended {
+		header = append(header, "Recommended")
+	}
+	t.AppendHeader(header)
+
+	// Rows are appended in order, results are sorted by sortResults already
+	for _, r := range results {
+		row := table.Row{r.Region}
+		if multiType {
+			row = append(row, r.AmiType)
+		}
+		if multiVersion {
+			row = append(row, r.KubernetesVersion)
+		}
+		row = append(row,
+			r.ImageId,
+			r.Name,
+			r.Description,
+			r.DeprecationTime,
+			r.Architecture,
+		)
+		for _, c := range metadataColumns {
+			row = append(row, c.value(r))
+		}
+		if recommended {
+			row = append(row, r.Recommended)
+		}
+		t.AppendRow(row)
+	}
+
+	t.Style().Format.Header = text.FormatDefault
+	t.Render()
+
+	return nil
+}
+
+func newSearchOutput(results []amiSearchResult, errs []amiSearchError) amiSearchOutput {
+	// Always emit lists, never null, so consumers can iterate safely
+	if results == nil {
+		results = []amiSearchResult{}
+	}
+	if errs == nil {
+		errs = []amiSearchError{}
+	}
+	return amiSearchOutput{
+		SchemaVersion: outputSchemaVersion,
+		Results:       results,
+		Errors:        errs,
+	}
+}
+
+func encodeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func encodeYAML(w io.Writer, v any) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func writeCSV(w io.Writer, header []string, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+func renderCSV(w io.Writer, results []amiSearchResult) error {
+	rows := make([][]string, 0, len(results))
+	for _, r := range results {
+		rows = append(rows, []string{
+			r.Region,
+			r.OwnerId,
+			r.NamePattern,
+			r.ImageId,
+			r.Name,
+			r.Description,
+			r.CreationDate,
+			r.DeprecationTime,
+			r.Architecture,
+			strconv.FormatBool(r.Recommended),
+			r.KubernetesVersion,
+			r.AmiType,
+			r.BottlerocketVersion,
+			r.BuildHash,
+			r.OSFamily,
+			r.Accelerator,
+			strconv.FormatBool(r.FIPS),
+			r.KubernetesPatchVersion,
+			r.ReleaseVersion,
+			r.ReleaseDate,
+			r.ContainerdVersion,
+		})
+	}
+
+	// Columns are only ever appended in the order they were introduced, consumers index them by position
+	return writeCSV(w, []string{
+		"region",
+		"ownerId",
+		"namePattern",
+		"imageId",
+		"name",
+		"description",
+		"creationDate",
+		"deprecationTime",
+		"architecture",
+		"recommended",
+		"kubernetesVersion",
+		"amiType",
+		"bottlerocketVersion",
+		"buildHash",
+		"osFamily",
+		"accelerator",
+		"fips",
+		"kubernetesPatchVersion",
+		"releaseVersion",
+		"releaseDate",
+		"containerdVersion",
+	}, rows)
+}
+
+// newestByRegionAndType keys the newest result by region then AMI type, for output formats pinning
+// a single AMI per slot. Results must be sorted by sortResults already.
+func newestByRegionAndType(results []amiSearchResult, format string) (map[string]map[string]amiSearchResult, error) {
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no matching AMI found")
+	}
+	if slices.ContainsFunc(results, func(r amiSearchResult) bool { return r.KubernetesVersion != results[0].KubernetesVersion }) {
+		return nil, fmt.Errorf("%s output is keyed by region and AMI type, look up a single Kubernetes version at a time", format)
+	}
+
+	m := make(map[string]map[string]amiSearchResult)
+	for _, r := range results {
+		if m[r.Region] == nil {
+			m[r.Region] = make(map[string]amiSearchResult)
+		}
+		if _, ok := m[r.Region][r.AmiType]; !ok {
+			m[r.Region][r.AmiType] = r
+		}
+	}
+	return m, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+	"github.com/guessi/eks-ami-finder/pkg/finder"
+	"github.com/guessi/eks-ami-finder/pkg/owners"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	ownerStatusMatch    = "match"
+	ownerStatusMismatch = "mismatch"
+	ownerStatusNew      = "new"
+	ownerStatusError    = "error"
+)
+
+var OwnersDiscoverFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:     "family",
+		Aliases:  []string{"f"},
+		Required: true,
+		Usage:    "AMI family to discover, one of: " + strings.Join(owners.DiscoverableFamilies, ", "),
+		Action: func(ctx context.Context, c *cli.Command, v string) error {
+			if !slices.Contains(owners.DiscoverableFamilies, v) {
+				return fmt.Errorf("invalid family '%s'. Valid families: %s", v, strings.Join(owners.DiscoverableFamilies, ", "))
+			}
+			return nil
+		},
+	},
+	&cli.StringFlag{
+		Name:    "region",
+		Aliases: []string{"r"},
+		Value:   "all",
+		Usage:   "Regions to discover, a comma-separated list or \"all\" for every commercial region",
+	},
+	&cli.StringFlag{
+		Name:  "write",
+		Usage: "Write the updated owner mappings to the given file, format is picked by extension (.go or .json)",
+		Action: func(ctx context.Context, c *cli.Command, v string) error {
+			if ext := filepath.Ext(v); ext != ".go" && ext != ".json" {
+				return fmt.Errorf("unsupported file extension '%s', expected .go or .json", ext)
+			}
+			return nil
+		},
+	},
+}
+
+func discoverOwners(ctx context.Context, family, kubernetesVersion string, regions []string) ([]ownerDiscoveryResult, error) {
+	builtin, err := owners.BuiltinMappings(family)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ownerDiscoveryResult, len(regions))
+	forEachRegion(regions, func(idx int, region string) {
+		r := ownerDiscoveryResult{
+			Family: family,
+			Region: region,
+		}
+		if v, ok := owners.LookupOwner(builtin, region); ok {
+			r.BuiltinOwnerId = v
+		}
+
+		cfg, err := loadAwsConfig(ctx, region)
+		if err == nil {
+			r.ImageId, r.DiscoveredOwnerId, err = owners.Discover(ctx, ssm.NewFromConfig(cfg), ec2.NewFromConfig(cfg), family, kubernetesVersion)
+		}
+
+		switch {
+		case err != nil:
+			r.Status = ownerStatusError
+			r.Error = err.Error()
+		case r.BuiltinOwnerId == "":
+			r.Status = ownerStatusNew
+		case r.BuiltinOwnerId != r.DiscoveredOwnerId:
+			r.Status = ownerStatusMismatch
+		default:
+			r.Status = ownerStatusMatch
+		}
+		results[idx] = r
+	})
+
+	return results, nil
+}
+
+func renderOwnerDiscoveryResults(w io.Writer, format string, results []ownerDiscoveryResult) error {
+	switch format {
+	case "", constants.OutputFormatTable:
+		t := table.NewWriter()
+		t.SetOutputMirror(w)
+		t.AppendHeader(table.Row{
+			"Family",
+			"Region",
+			"AMI ID",
+			"Discovered Owner ID",
+			"Built-in Owner ID",
+			"Status",
+		})
+		for _, r := range results {
+			status := r.Status
+			if r.Error != "" {
+				status = fmt.Sprintf("%s: %s", r.Status, r.Error)
+			}
+			t.AppendRow(table.Row{
+				r.Family,
+				r.Region,
+				r.ImageId,
+				r.DiscoveredOwnerId,
+				r.BuiltinOwnerId,
+				status,
+			})
+		}
+		t.Style().Format.Header = text.FormatDefault
+		t.Render()
+		return nil
+	case constants.OutputFormatJSON:
+		return encodeJSON(w, ownerDiscoveryOutput{SchemaVersion: outputSchemaVersion, Results: results})
+	case constants.OutputFormatYAML:
+		return encodeYAML(w, ownerDiscoveryOutput{SchemaVersion: outputSchemaVersion, Results: results})
+	case constants.OutputFormatCSV:
+		rows := make([][]string, 0, len(results))
+		for _, r := range results {
+			rows = append(rows, []string{
+				r.Family,
+				r.Region,
+				r.ImageId,
+				r.DiscoveredOwnerId,
+				r.BuiltinOwnerId,
+				r.Status,
+				r.Error,
+			})
+		}
+		return writeCSV(w, []string{
+			"family",
+			"region",
+			"imageId",
+			"discoveredOwnerId",
+			"builtinOwnerId",
+			"status",
+			"error",
+		}, rows)
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+func writeOwnerMappings(path, family string, results []ownerDiscoveryResult) error {
+	builtin, err := owners.BuiltinMappings(family)
+	if err != nil {
+		return err
+	}
+
+	discovered := map[string]string{}
+	for _, r := range results {
+		if r.Status != ownerStatusError {
+			discovered[r.Region] = r.DiscoveredOwnerId
+		}
+	}
+	merged := owners.Merge(builtin, discovered)
+
+	// Generate everything first, a failure must not leave a truncated file behind
+	var data []byte
+	switch filepath.Ext(path) {
+	case ".go":
+		if data, err = owners.GoSource(family, merged); err != nil {
+			return err
+		}
+	default:
+		var buf bytes.Buffer
+		if err := encodeJSON(&buf, map[string]map[string]string{family: merged}); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+func OwnersDiscover(ctx context.Context, c *cli.Command) error {
+	ctx, cancel := context.WithTimeout(ctx, c.Duration("timeout"))
+	defer cancel()
+
+	regions, err := parseRegions(c.String("region"))
+	if err != nil {
+		return err
+	}
+
+	versions, err := finder.ExpandKubernetesVersions(c.String("kubernetes-version"))
+	if err != nil {
+		return err
+	}
+
+	// Owners don't change across versions, the newest one given is good enough
+	family := c.String("family")
+	results, err := discoverOwners(ctx, family, versions[len(versions)-1], regions)
+	if err != nil {
+		return err
+	}
+
+	if err := renderOwnerDiscoveryResults(os.Stdout, c.String("output"), results); err != nil {
+		return fmt.Errorf("unable to render results: %v", err)
+	}
+
+	if path := c.String("write"); path != "" {
+		if err := writeOwnerMappings(path, family, results); err != nil {
+			return fmt.Errorf("unable to write owner mappings: %v", err)
+		}
+	}
+
+	var failed, drifted int
+	for _, r := range results {
+		switch r.Status {
+		case ownerStatusError:
+			failed++
+		case ownerStatusMismatch, ownerStatusNew:
+			drifted++
+		}
+	}
+	switch {
+	case failed > 0 && drifted > 0:
+		return fmt.Errorf("%d region(s) differ from built-in owner mappings, %d region(s) failed", drifted, failed)
+	case drifted > 0:
+		return fmt.Errorf("%d region(s) differ from built-in owner mappings", drifted)
+	case failed > 0:
+		return fmt.Errorf("%d region(s) failed", failed)
+	}
+
+	return nil
+}
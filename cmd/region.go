@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+)
+
+// Upper bound of regions being queried at the same time
+const maxConcurrentRegionSearches = 8
+
+type regionSearchOutcome struct {
+	Region  string
+	Results []amiSearchResult
+	Err     error
+}
+
+// allRegions returns every commercial region with a known Bottlerocket owner,
+// which is the most complete of the owner mapping tables.
+func allRegions() []string {
+	regions := make([]string, 0, len(constants.AwsAccountMappingsBottlerocket))
+	for region := range constants.AwsAccountMappingsBottlerocket {
+		// China and GovCloud partitions require dedicated credentials, opt-in explicitly
+		if strings.HasPrefix(region, "cn-") || strings.HasPrefix(region, "us-gov-") {
+			continue
+		}
+		regions = append(regions, region)
+	}
+	slices.Sort(regions)
+	return regions
+}
+
+// parseRegions expands the --region input, a single region, comma-separated list or "all"
+func parseRegions(v string) ([]string, error) {
+	var regions []string
+	for _, region := range strings.Split(v, ",") {
+		region = strings.TrimSpace(region)
+		if region == "" {
+			continue
+		}
+		if region == "all" {
+			return allRegions(), nil
+		}
+		if !slices.Contains(regions, region) {
+			regions = append(regions, region)
+		}
+	}
+
+	if len(regions) == 0 {
+		return nil, fmt.Errorf("region must not be empty")
+	}
+
+	return regions, nil
+}
+
+// forEachRegion calls fn for every region with a bounded worker pool and waits for all of them
+func forEachRegion(regions []string, fn func(idx int, region string)) {
+	sem := make(chan struct{}, maxConcurrentRegionSearches)
+
+	var wg sync.WaitGroup
+	for idx, region := range regions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			fn(idx, region)
+		}()
+	}
+	wg.Wait()
+}
+
+// multiRegionSearch runs amiSearch for each input concurrently,
+// outcomes are returned in the same order as inputs.
+func multiRegionSearch(ctx context.Context, inputs []amiSearchInputSpec) []regionSearchOutcome {
+	regions := make([]string, 0, len(inputs))
+	for _, input := range inputs {
+		regions = append(regions, input.AWS_REGION)
+	}
+
+	outcomes := make([]regionSearchOutcome, len(inputs))
+	forEachRegion(regions, func(idx int, region string) {
+		results, err := amiSearch(ctx, inputs[idx])
+		outcomes[idx] = regionSearchOutcome{
+			Region:  region,
+			Results: results,
+			Err:     err,
+		}
+	})
+
+	return outcomes
+}
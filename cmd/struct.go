@@ -1,13 +1,208 @@
 package cmd
 
+import "time"
+
 type amiSearchInputSpec struct {
-	AWS_REGION         string
-	AMI_OWNER_ID       string
-	AMI_TYPE           string
-	KUBERNETES_VERSION string
-	RELEASE_DATE       string
-	MAX_RESULTS        int
-	AUTO_MODE          bool
-	INCLUDE_DEPRECATED bool
-	DEBUG_MODE         bool
+	AWS_REGION               string
+	AMI_OWNER_ID             string
+	AMI_TYPE                 string
+	KUBERNETES_VERSION       string
+	RELEASE_DATE             string
+	SINCE                    time.Time
+	UNTIL                    time.Time
+	BOTTLEROCKET_VERSION     string
+	TERRAFORM_VARIABLE       string
+	TERRAFORM_DATA_SOURCE    bool
+	EKSCTL_NODE_GROUPS       string
+	CLOUDFORMATION_PARAMETER string
+	TEMPLATE                 string
+	TEMPLATE_FILE            string
+	TEMPLATE_SCOPE           string
+	OUTPUT_FORMAT            string
+	MAX_RESULTS              int
+	AUTO_MODE                bool
+	INCLUDE_DEPRECATED       bool
+	RECOMMENDED              bool
+	DEBUG_MODE               bool
+}
+
+type amiSearchResult struct {
+	Region            string `json:"region" yaml:"region"`
+	AmiType           string `json:"amiType" yaml:"amiType"`
+	KubernetesVersion string `json:"kubernetesVersion" yaml:"kubernetesVersion"`
+	OwnerId           string `json:"ownerId" yaml:"ownerId"`
+	NamePattern       string `json:"namePattern" yaml:"namePattern"`
+	ImageId           string `json:"imageId" yaml:"imageId"`
+	Name              string `json:"name" yaml:"name"`
+	Description       string `json:"description" yaml:"description"`
+	CreationDate      string `json:"creationDate" yaml:"creationDate"`
+	DeprecationTime   string `json:"deprecationTime" yaml:"deprecationTime"`
+	Architecture      string `json:"architecture" yaml:"architecture"`
+	Recommended       bool   `json:"recommended" yaml:"recommended"`
+
+	BottlerocketVersion string `json:"bottlerocketVersion,omitempty" yaml:"bottlerocketVersion,omitempty"`
+	BuildHash           string `json:"buildHash,omitempty" yaml:"buildHash,omitempty"`
+
+	// Parsed from name and description
+	OSFamily               string `json:"osFamily,omitempty" yaml:"osFamily,omitempty"`
+	Accelerator            string `json:"accelerator,omitempty" yaml:"accelerator,omitempty"`
+	FIPS                   bool   `json:"fips" yaml:"fips"`
+	KubernetesPatchVersion string `json:"kubernetesPatchVersion,omitempty" yaml:"kubernetesPatchVersion,omitempty"`
+	ReleaseVersion         string `json:"releaseVersion,omitempty" yaml:"releaseVersion,omitempty"`
+	ReleaseDate            string `json:"releaseDate,omitempty" yaml:"releaseDate,omitempty"`
+	ContainerdVersion      string `json:"containerdVersion,omitempty" yaml:"containerdVersion,omitempty"`
+}
+
+type amiSearchError struct {
+	Region            string `json:"region" yaml:"region"`
+	AmiType           string `json:"amiType,omitempty" yaml:"amiType,omitempty"`
+	KubernetesVersion string `json:"kubernetesVersion,omitempty" yaml:"kubernetesVersion,omitempty"`
+	Error             string `json:"error" yaml:"error"`
+}
+
+func (e amiSearchError) label() string {
+	label := e.Region
+	for _, v := range []string{e.AmiType, e.KubernetesVersion} {
+		if v != "" {
+			label += " " + v
+		}
+	}
+	return label
+}
+
+type amiSearchOutput struct {
+	SchemaVersion string            `json:"schemaVersion" yaml:"schemaVersion"`
+	Results       []amiSearchResult `json:"results" yaml:"results"`
+	Errors        []amiSearchError  `json:"errors" yaml:"errors"`
+}
+
+type amiInspectResult struct {
+	Region            string `json:"region" yaml:"region"`
+	ImageId           string `json:"imageId" yaml:"imageId"`
+	Name              string `json:"name" yaml:"name"`
+	OwnerId           string `json:"ownerId" yaml:"ownerId"`
+	AmiType           string `json:"amiType" yaml:"amiType"`
+	KubernetesVersion string `json:"kubernetesVersion" yaml:"kubernetesVersion"`
+	ReleaseVersion    string `json:"releaseVersion" yaml:"releaseVersion"`
+	CreationDate      string `json:"creationDate" yaml:"creationDate"`
+	DeprecationTime   string `json:"deprecationTime" yaml:"deprecationTime"`
+	Identified        bool   `json:"identified" yaml:"identified"`
+	Official          bool   `json:"official" yaml:"official"`
+}
+
+type amiInspectOutput struct {
+	SchemaVersion string             `json:"schemaVersion" yaml:"schemaVersion"`
+	Results       []amiInspectResult `json:"results" yaml:"results"`
+}
+
+type amiVerifyResult struct {
+	Region          string   `json:"region" yaml:"region"`
+	ImageId         string   `json:"imageId" yaml:"imageId"`
+	Name            string   `json:"name" yaml:"name"`
+	AmiType         string   `json:"amiType" yaml:"amiType"`
+	OwnerId         string   `json:"ownerId" yaml:"ownerId"`
+	ExpectedOwnerId string   `json:"expectedOwnerId" yaml:"expectedOwnerId"`
+	Passed          bool     `json:"passed" yaml:"passed"`
+	Problems        []string `json:"problems" yaml:"problems"`
+	Status          string   `json:"status" yaml:"status"`
+	OverrideSource  string   `json:"overrideSource,omitempty" yaml:"overrideSource,omitempty"`
+}
+
+type amiVerifyOutput struct {
+	SchemaVersion string            `json:"schemaVersion" yaml:"schemaVersion"`
+	Results       []amiVerifyResult `json:"results" yaml:"results"`
+}
+
+type ownerDiscoveryResult struct {
+	Family            string `json:"family" yaml:"family"`
+	Region            string `json:"region" yaml:"region"`
+	ImageId           string `json:"imageId" yaml:"imageId"`
+	DiscoveredOwnerId string `json:"discoveredOwnerId" yaml:"discoveredOwnerId"`
+	BuiltinOwnerId    string `json:"builtinOwnerId" yaml:"builtinOwnerId"`
+	Status            string `json:"status" yaml:"status"`
+	Error             string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type ownerDiscoveryOutput struct {
+	SchemaVersion string                 `json:"schemaVersion" yaml:"schemaVersion"`
+	Results       []ownerDiscoveryResult `json:"results" yaml:"results"`
+}
+
+type amiBump struct {
+	Region            string `json:"region" yaml:"region"`
+	AmiType           string `json:"amiType" yaml:"amiType"`
+	KubernetesVersion string `json:"kubernetesVersion" yaml:"kubernetesVersion"`
+	From              string `json:"from" yaml:"from"`
+	FromName          string `json:"fromName" yaml:"fromName"`
+	To                string `json:"to" yaml:"to"`
+	ToName            string `json:"toName" yaml:"toName"`
+}
+
+type amiBumpOutput struct {
+	SchemaVersion string    `json:"schemaVersion" yaml:"schemaVersion"`
+	Results       []amiBump `json:"results" yaml:"results"`
+}
+
+type cacheStats struct {
+	Dir     string `json:"dir" yaml:"dir"`
+	TTL     string `json:"ttl" yaml:"ttl"`
+	Entries int    `json:"entries" yaml:"entries"`
+	Fresh   int    `json:"fresh" yaml:"fresh"`
+	Stale   int    `json:"stale" yaml:"stale"`
+	Invalid int    `json:"invalid" yaml:"invalid"`
+	Images  int    `json:"images" yaml:"images"`
+	Bytes   int64  `json:"bytes" yaml:"bytes"`
+	Oldest  string `json:"oldest" yaml:"oldest"`
+	Newest  string `json:"newest" yaml:"newest"`
+}
+
+type cacheStatsOutput struct {
+	SchemaVersion string     `json:"schemaVersion" yaml:"schemaVersion"`
+	Stats         cacheStats `json:"stats" yaml:"stats"`
+}
+
+type compatibilityRow struct {
+	AmiType              string   `json:"amiType" yaml:"amiType"`
+	AutoMode             bool     `json:"autoMode" yaml:"autoMode"`
+	MinKubernetesVersion string   `json:"minKubernetesVersion,omitempty" yaml:"minKubernetesVersion,omitempty"`
+	MaxKubernetesVersion string   `json:"maxKubernetesVersion,omitempty" yaml:"maxKubernetesVersion,omitempty"`
+	SupportedVersions    []string `json:"supportedVersions" yaml:"supportedVersions"`
+	Sources              []string `json:"sources" yaml:"sources"`
+}
+
+type compatibilityOutput struct {
+	SchemaVersion      string             `json:"schemaVersion" yaml:"schemaVersion"`
+	KubernetesVersions []string           `json:"kubernetesVersions" yaml:"kubernetesVersions"`
+	Results            []compatibilityRow `json:"results" yaml:"results"`
+}
+
+type amiTypeRow struct {
+	AmiType       string `json:"amiType" yaml:"amiType"`
+	AutoMode      bool   `json:"autoMode" yaml:"autoMode"`
+	OSFamily      string `json:"osFamily" yaml:"osFamily"`
+	Architecture  string `json:"architecture" yaml:"architecture"`
+	Accelerator   string `json:"accelerator,omitempty" yaml:"accelerator,omitempty"`
+	FIPS          bool   `json:"fips" yaml:"fips"`
+	OwnerFamily   string `json:"ownerFamily" yaml:"ownerFamily"`
+	NamePattern   string `json:"namePattern" yaml:"namePattern"`
+	ReleaseFilter bool   `json:"releaseFilter" yaml:"releaseFilter"`
+}
+
+type amiTypesOutput struct {
+	SchemaVersion string       `json:"schemaVersion" yaml:"schemaVersion"`
+	Results       []amiTypeRow `json:"results" yaml:"results"`
+}
+
+type versionRow struct {
+	KubernetesVersion    string `json:"kubernetesVersion" yaml:"kubernetesVersion"`
+	Status               string `json:"status" yaml:"status"`
+	Latest               bool   `json:"latest" yaml:"latest"`
+	ReleaseDate          string `json:"releaseDate,omitempty" yaml:"releaseDate,omitempty"`
+	EndOfStandardSupport string `json:"endOfStandardSupport,omitempty" yaml:"endOfStandardSupport,omitempty"`
+	EndOfExtendedSupport string `json:"endOfExtendedSupport,omitempty" yaml:"endOfExtendedSupport,omitempty"`
+}
+
+type versionsOutput struct {
+	SchemaVersion string       `json:"schemaVersion" yaml:"schemaVersion"`
+	Results       []versionRow `json:"results" yaml:"results"`
 }
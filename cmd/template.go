@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/guessi/eks-ami-finder/pkg/finder"
+)
+
+// Template scopes, the template is executed for each result or once for the whole result set
+const (
+	templateScopeResult = "result"
+	templateScopeAll    = "all"
+)
+
+// templateTime accepts the timestamps found in results, EC2 layout strings or time.Time
+func templateTime(v any) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		if t == "" {
+			return time.Time{}, nil
+		}
+		for _, layout := range []string{finder.TimeLayout, time.RFC3339} {
+			if parsed, err := time.Parse(layout, t); err == nil {
+				return parsed, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("unable to parse time '%s'", t)
+	}
+	return time.Time{}, fmt.Errorf("unable to parse time of type %T", v)
+}
+
+// templateFuncs are the helpers available to --template, now is fixed for a whole run
+func templateFuncs(now time.Time) template.FuncMap {
+	daysUntil := func(v any) (int, error) {
+		t, err := templateTime(v)
+		if err != nil || t.IsZero() {
+			return 0, err
+		}
+		return int(math.Floor(t.Sub(now).Hours() / 24)), nil
+	}
+
+	return template.FuncMap{
+		// {{date "2006-01-02" .CreationDate}}
+		"date": func(layout string, v any) (string, error) {
+			t, err := templateTime(v)
+			if err != nil || t.IsZero() {
+				return "", err
+			}
+			return t.UTC().Format(layout), nil
+		},
+		// {{daysUntil .DeprecationTime}}, negative once passed
+		"daysUntil": daysUntil,
+		// {{daysSince .CreationDate}}
+		"daysSince": func(v any) (int, error) {
+			days, err := daysUntil(v)
+			return -days, err
+		},
+		// {{deprecation .DeprecationTime}}, e.g. "in 412 days", "3 days ago" or "never"
+		"deprecation": func(v any) (string, error) {
+			t, err := templateTime(v)
+			if err != nil {
+				return "", err
+			}
+			if t.IsZero() {
+				return "never", nil
+			}
+			days, _ := daysUntil(t)
+			if days >= 0 {
+				return fmt.Sprintf("in %d days", days), nil
+			}
+			return fmt.Sprintf("%d days ago", -days), nil
+		},
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+		"join":  strings.Join,
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+	}
+}
+
+// newOutputTemplate parses the inline template or the template file, at most one of them is set
+func newOutputTemplate(inline, file string, now time.Time) (*template.Template, error) {
+	if inline != "" && file != "" {
+		return nil, fmt.Errorf("template and template-file can not be used together")
+	}
+
+	text := inline
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read template file: %v", err)
+		}
+		text = string(data)
+	}
+
+	tmpl, err := template.New("output").Funcs(templateFuncs(now)).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// renderTemplate executes the template for each result, or once with the whole output document
+func renderTemplate(w io.Writer, input amiSearchInputSpec, results []amiSearchResult, errs []amiSearchError) error {
+	tmpl, err := newOutputTemplate(input.TEMPLATE, input.TEMPLATE_FILE, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if input.TEMPLATE_SCOPE == templateScopeAll {
+		return tmpl.Execute(w, newSearchOutput(results, errs))
+	}
+
+	for _, r := range results {
+		var b strings.Builder
+		if err := tmpl.Execute(&b, r); err != nil {
+			return err
+		}
+		// Each result goes on its own line, unless the template ends the line itself
+		out := b.String()
+		if !strings.HasSuffix(out, "\n") {
+			out += "\n"
+		}
+		if _, err := io.WriteString(w, out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
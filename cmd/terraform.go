@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	terraformIdentifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+	terraformNameCleanRegex  = regexp.MustCompile(`[^a-z0-9_]+`)
+)
+
+// renderTfvars writes a .tfvars.json document with a map of region to AMI type to AMI ID
+func renderTfvars(w io.Writer, variable string, results []amiSearchResult) error {
+	m, err := newestByRegionAndType(results, "terraform")
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]map[string]string, len(m))
+	for region, byType := range m {
+		ids[region] = make(map[string]string, len(byType))
+		for amiType, r := range byType {
+			ids[region][amiType] = r.ImageId
+		}
+	}
+
+	return encodeJSON(w, map[string]any{variable: ids})
+}
+
+// renderHCL writes the same map as renderTfvars in HCL, aligned the way "terraform fmt" does,
+// optionally followed by aws_ami data blocks reproducing the search.
+func renderHCL(w io.Writer, input amiSearchInputSpec, results []amiSearchResult) error {
+	variable := input.TERRAFORM_VARIABLE
+	m, err := newestByRegionAndType(results, "terraform")
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "%s = {\n", variable)
+	for _, region := range sortedKeys(m) {
+		fmt.Fprintf(w, "  %q = {\n", region)
+
+		amiTypes := sortedKeys(m[region])
+		width := 0
+		for _, amiType := range amiTypes {
+			width = max(width, len(amiType)+2)
+		}
+		for _, amiType := range amiTypes {
+			fmt.Fprintf(w, "    %-*s = %q\n", width, fmt.Sprintf("%q", amiType), m[region][amiType].ImageId)
+		}
+
+		fmt.Fprintf(w, "  }\n")
+	}
+	fmt.Fprintf(w, "}\n")
+
+	if !input.TERRAFORM_DATA_SOURCE {
+		return nil
+	}
+
+	// aws_ami has no counterpart for these, the data blocks could resolve AMIs the search left out
+	var dropped []string
+	if !input.SINCE.IsZero() || !input.UNTIL.IsZero() {
+		dropped = append(dropped, "--since/--until")
+	}
+	if input.BOTTLEROCKET_VERSION != "" {
+		dropped = append(dropped, "--bottlerocket-version")
+	}
+
+	for _, region := range sortedKeys(m) {
+		for _, amiType := range sortedKeys(m[region]) {
+			r := m[region][amiType]
+
+			// Recommended AMIs are resolved through SSM, pin the exact name instead
+			pattern := r.NamePattern
+			if pattern == "" {
+				pattern = r.Name
+			}
+
+			name := terraformNameCleanRegex.ReplaceAllString(strings.ToLower(region+"_"+amiType), "_")
+			fmt.Fprintf(w, "\n# %s, the aws provider must be configured for this region\n", region)
+			if len(dropped) > 0 && r.NamePattern != "" {
+				fmt.Fprintf(w, "# %s not carried over, most_recent may pick an AMI other than %s\n", strings.Join(dropped, " and "), r.ImageId)
+			}
+			fmt.Fprintf(w, "data \"aws_ami\" %q {\n", name)
+			fmt.Fprintf(w, "  most_recent        = true\n")
+			fmt.Fprintf(w, "  include_deprecated = %t\n", input.INCLUDE_DEPRECATED)
+			fmt.Fprintf(w, "  owners             = [%q]\n", r.OwnerId)
+			fmt.Fprintf(w, "\n")
+			fmt.Fprintf(w, "  filter {\n")
+			fmt.Fprintf(w, "    name   = \"name\"\n")
+			fmt.Fprintf(w, "    values = [%q]\n", pattern)
+			fmt.Fprintf(w, "  }\n")
+			fmt.Fprintf(w, "}\n")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+	"github.com/guessi/eks-ami-finder/pkg/finder"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/urfave/cli/v3"
+)
+
+func newAmiTypeRow(t constants.AmiType) amiTypeRow {
+	return amiTypeRow{
+		AmiType:       t.Name,
+		AutoMode:      t.AutoMode,
+		OSFamily:      t.OSFamily,
+		Architecture:  t.Architecture,
+		Accelerator:   t.Accelerator,
+		FIPS:          t.FIPS,
+		OwnerFamily:   t.OwnerFamily,
+		NamePattern:   t.NamePattern,
+		ReleaseFilter: t.ReleaseFilter,
+	}
+}
+
+func renderAmiTypes(w io.Writer, format string, rows []amiTypeRow) error {
+	switch format {
+	case "", constants.OutputFormatTable:
+		t := table.NewWriter()
+		t.SetOutputMirror(w)
+		t.AppendHeader(table.Row{"AMI Type", "Auto Mode", "OS Family", "Architecture", "Accelerator", "FIPS", "Owner Family", "Release Filter"})
+		for _, r := range rows {
+			t.AppendRow(table.Row{
+				r.AmiType,
+				r.AutoMode,
+				r.OSFamily,
+				r.Architecture,
+				r.Accelerator,
+				r.FIPS,
+				r.OwnerFamily,
+				r.ReleaseFilter,
+			})
+		}
+		t.Style().Format.Header = text.FormatDefault
+		t.Render()
+		return nil
+	case constants.OutputFormatJSON:
+		return encodeJSON(w, amiTypesOutput{SchemaVersion: outputSchemaVersion, Results: rows})
+	case constants.OutputFormatYAML:
+		return encodeYAML(w, amiTypesOutput{SchemaVersion: outputSchemaVersion, Results: rows})
+	case constants.OutputFormatCSV:
+		header := []string{
+			"amiType",
+			"autoMode",
+			"osFamily",
+			"architecture",
+			"accelerator",
+			"fips",
+			"ownerFamily",
+			"namePattern",
+			"releaseFilter",
+		}
+		records := make([][]string, 0, len(rows))
+		for _, r := range rows {
+			records = append(records, []string{
+				r.AmiType,
+				strconv.FormatBool(r.AutoMode),
+				r.OSFamily,
+				r.Architecture,
+				r.Accelerator,
+				strconv.FormatBool(r.FIPS),
+				r.OwnerFamily,
+				r.NamePattern,
+				strconv.FormatBool(r.ReleaseFilter),
+			})
+		}
+		return writeCSV(w, header, records)
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// Types prints the registry of AMI types, every AMI type is listed unless --ami-type or --auto-mode is given.
+func Types(ctx context.Context, c *cli.Command) error {
+	autoMode := c.Bool("auto-mode")
+
+	var names []string
+	if c.IsSet("ami-type") {
+		var err error
+		if names, err = finder.ExpandAmiTypes(c.String("ami-type"), autoMode); err != nil {
+			return err
+		}
+	}
+
+	rows := make([]amiTypeRow, 0, len(constants.AmiTypes))
+	for _, t := range constants.AmiTypes {
+		if (c.IsSet("auto-mode") || names != nil) && t.AutoMode != autoMode {
+			continue
+		}
+		if names != nil && !slices.Contains(names, t.Name) {
+			continue
+		}
+		rows = append(rows, newAmiTypeRow(t))
+	}
+
+	if err := renderAmiTypes(os.Stdout, c.String("output"), rows); err != nil {
+		return fmt.Errorf("unable to render results: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+	"github.com/guessi/eks-ami-finder/pkg/finder"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/urfave/cli/v3"
+)
+
+// amiVerify verifies each AMI in the region it is found in, AMIs found in none of the regions fail once
+func amiVerify(ctx context.Context, regions, imageIds []string) ([]amiVerifyResult, error) {
+	verifications := make([][]finder.Verification, len(regions))
+	errs := make([]error, len(regions))
+	forEachRegion(regions, func(idx int, region string) {
+		svc, err := trustedImageClient(ctx, region)
+		if err != nil {
+			errs[idx] = err
+			return
+		}
+		verifications[idx], errs[idx] = finder.New(svc).Verify(ctx, region, imageIds)
+	})
+	for idx, region := range regions {
+		if errs[idx] != nil {
+			return nil, fmt.Errorf("[%s] %v", region, errs[idx])
+		}
+	}
+
+	// Verify returns one verification per requested ID, in order
+	results := make([]amiVerifyResult, 0, len(imageIds))
+	for pos, id := range imageIds {
+		idx := slices.IndexFunc(verifications, func(vs []finder.Verification) bool { return vs[pos].Found })
+		switch {
+		case idx >= 0:
+			results = append(results, verifyResult(regions[idx], verifications[idx][pos]))
+		case len(regions) == 1:
+			results = append(results, verifyResult(regions[0], verifications[0][pos]))
+		default:
+			results = append(results, amiVerifyResult{
+				Region:   strings.Join(regions, ","),
+				ImageId:  id,
+				Problems: []string{fmt.Sprintf("AMI not found or not accessible in any of %d regions", len(regions))},
+				Status:   finder.VerificationFailed,
+			})
+		}
+	}
+
+	return results, nil
+}
+
+func verifyResult(region string, v finder.Verification) amiVerifyResult {
+	problems := v.Problems
+	if problems == nil {
+		problems = []string{}
+	}
+	return amiVerifyResult{
+		Region:          region,
+		ImageId:         v.ImageId,
+		Name:            v.Inspection.Image.Name,
+		AmiType:         v.Inspection.Identity.AmiType,
+		OwnerId:         v.Inspection.Image.OwnerId,
+		ExpectedOwnerId: v.ExpectedOwnerId,
+		Passed:          v.Passed(),
+		Problems:        problems,
+		Status:          v.Status(),
+		OverrideSource:  v.OverrideSource,
+	}
+}
+
+func renderVerifyResults(w io.Writer, format string, results []amiVerifyResult) error {
+	if results == nil {
+		results = []amiVerifyResult{}
+	}
+	output := amiVerifyOutput{
+		SchemaVersion: outputSchemaVersion,
+		Results:       results,
+	}
+
+	switch format {
+	case "", constants.OutputFormatTable:
+		t := table.NewWriter()
+		t.SetOutputMirror(w)
+		t.AppendHeader(table.Row{
+			"Region",
+			"AMI ID",
+			"AMI Type",
+			"Owner ID",
+			"Expected Owner ID",
+			"Result",
+			"Problems",
+		})
+		for _, r := range results {
+			t.AppendRow(table.Row{
+				r.Region,
+				r.ImageId,
+				r.AmiType,
+				r.OwnerId,
+				r.ExpectedOwnerId,
+				strings.ToUpper(r.Status),
+				strings.Join(r.Problems, "\n"),
+			})
+		}
+		t.Style().Format.Header = text.FormatDefault
+		t.Render()
+		return nil
+	case constants.OutputFormatJSON:
+		return encodeJSON(w, output)
+	case constants.OutputFormatYAML:
+		return encodeYAML(w, output)
+	case constants.OutputFormatCSV:
+		rows := make([][]string, 0, len(results))
+		for _, r := range results {
+			rows = append(rows, []string{
+				r.Region,
+				r.ImageId,
+				r.Name,
+				r.AmiType,
+				r.OwnerId,
+				r.ExpectedOwnerId,
+				strconv.FormatBool(r.Passed),
+				strings.Join(r.Problems, "; "),
+				r.Status,
+				r.OverrideSource,
+			})
+		}
+		return writeCSV(w, []string{
+			"region",
+			"imageId",
+			"name",
+			"amiType",
+			"ownerId",
+			"expectedOwnerId",
+			"passed",
+			"problems",
+			"status",
+			"overrideSource",
+		}, rows)
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+func Verify(ctx context.Context, c *cli.Command) error {
+	ctx, cancel := context.WithTimeout(ctx, c.Duration("timeout"))
+	defer cancel()
+
+	if err := applySharedFlags(c); err != nil {
+		return err
+	}
+
+	imageIds, err := imageIdsFromArgs(c)
+	if err != nil {
+		return err
+	}
+
+	regions, err := parseRegions(c.String("region"))
+	if err != nil {
+		return err
+	}
+
+	results, err := amiVerify(ctx, regions, imageIds)
+	if err != nil {
+		return err
+	}
+
+	if err := renderVerifyResults(os.Stdout, c.String("output"), results); err != nil {
+		return fmt.Errorf("unable to render results: %v", err)
+	}
+
+	failed := 0
+	for _, r := range results {
+		if !r.Passed {
+			failed++
+		}
+		if r.Status == finder.VerificationOverride {
+			fmt.Fprintf(os.Stderr, "Warning: owner %s of %s is only trusted by the owner mappings in %s, not by the built-in owner mappings\n", r.OwnerId, r.ImageId, r.OverrideSource)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("verification failed for %d of %d AMI(s)", failed, len(results))
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+	"github.com/guessi/eks-ami-finder/pkg/finder"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/urfave/cli/v3"
+)
+
+func newVersionRow(l finder.Lifecycle, latest string) versionRow {
+	date := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(finder.LifecycleDateLayout)
+	}
+
+	return versionRow{
+		KubernetesVersion:    l.Version,
+		Status:               l.Status,
+		Latest:               l.Version == latest,
+		ReleaseDate:          date(l.ReleaseDate),
+		EndOfStandardSupport: date(l.EndOfStandardSupport),
+		EndOfExtendedSupport: date(l.EndOfExtendedSupport),
+	}
+}
+
+func renderVersions(w io.Writer, format string, rows []versionRow) error {
+	switch format {
+	case "", constants.OutputFormatTable:
+		t := table.NewWriter()
+		t.SetOutputMirror(w)
+		t.AppendHeader(table.Row{"Kubernetes Version", "Status", "Release Date", "End of Standard Support", "End of Extended Support"})
+		for _, r := range rows {
+			version := r.KubernetesVersion
+			if r.Latest {
+				version += " (latest)"
+			}
+			t.AppendRow(table.Row{
+				version,
+				r.Status,
+				r.ReleaseDate,
+				r.EndOfStandardSupport,
+				r.EndOfExtendedSupport,
+			})
+		}
+		t.Style().Format.Header = text.FormatDefault
+		t.Render()
+		return nil
+	case constants.OutputFormatJSON:
+		return encodeJSON(w, versionsOutput{SchemaVersion: outputSchemaVersion, Results: rows})
+	case constants.OutputFormatYAML:
+		return encodeYAML(w, versionsOutput{SchemaVersion: outputSchemaVersion, Results: rows})
+	case constants.OutputFormatCSV:
+		header := []string{
+			"kubernetesVersion",
+			"status",
+			"latest",
+			"releaseDate",
+			"endOfStandardSupport",
+			"endOfExtendedSupport",
+		}
+		records := make([][]string, 0, len(rows))
+		for _, r := range rows {
+			records = append(records, []string{
+				r.KubernetesVersion,
+				r.Status,
+				strconv.FormatBool(r.Latest),
+				r.ReleaseDate,
+				r.EndOfStandardSupport,
+				r.EndOfExtendedSupport,
+			})
+		}
+		return writeCSV(w, header, records)
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// Versions prints the lifecycle of Amazon EKS versions, every version of the lifecycle table is listed
+// unless --kubernetes-version is given.
+func Versions(ctx context.Context, c *cli.Command) error {
+	now := time.Now()
+	latest := finder.LatestKubernetesVersion(now)
+
+	var lifecycles []finder.Lifecycle
+	if c.IsSet("kubernetes-version") {
+		versions, err := finder.ExpandKubernetesVersions(c.String("kubernetes-version"))
+		if err != nil {
+			return err
+		}
+		for _, v := range versions {
+			lifecycles = append(lifecycles, finder.KubernetesLifecycle(v, now))
+		}
+	} else {
+		lifecycles = finder.KubernetesLifecycles(now)
+	}
+
+	rows := make([]versionRow, 0, len(lifecycles))
+	for _, l := range lifecycles {
+		rows = append(rows, newVersionRow(l, latest))
+	}
+
+	if err := renderVersions(os.Stdout, c.String("output"), rows); err != nil {
+		return fmt.Errorf("unable to render results: %v", err)
+	}
+	return nil
+}
@@ -2,59 +2,191 @@ package cmd
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
 	"strings"
+	"time"
 
-	"github.com/guessi/eks-ami-finder/pkg/constants"
+	"github.com/guessi/eks-ami-finder/pkg/finder"
 	"github.com/urfave/cli/v3"
 )
 
 func amiSearchInput(c *cli.Command) amiSearchInputSpec {
+	// Both inputs were validated by the flag actions already
+	now := time.Now()
+	since, _ := parseOptionalTime(c.String("since"), now, finder.ParseSince)
+	until, _ := parseOptionalTime(c.String("until"), now, finder.ParseUntil)
+
 	return amiSearchInputSpec{
-		AWS_REGION:         c.String("region"),
-		AMI_OWNER_ID:       c.String("owner-id"),
-		AMI_TYPE:           c.String("ami-type"),
-		KUBERNETES_VERSION: c.String("kubernetes-version"),
-		RELEASE_DATE:       c.String("release-date"),
-		MAX_RESULTS:        c.Int("max-results"),
-		AUTO_MODE:          c.Bool("auto-mode"),
-		INCLUDE_DEPRECATED: c.Bool("include-deprecated"),
-		DEBUG_MODE:         c.Bool("debug"),
+		AWS_REGION:               c.String("region"),
+		AMI_OWNER_ID:             c.String("owner-id"),
+		AMI_TYPE:                 c.String("ami-type"),
+		KUBERNETES_VERSION:       c.String("kubernetes-version"),
+		RELEASE_DATE:             c.String("release-date"),
+		SINCE:                    since,
+		UNTIL:                    until,
+		BOTTLEROCKET_VERSION:     c.String("bottlerocket-version"),
+		TERRAFORM_VARIABLE:       c.String("terraform-variable"),
+		TERRAFORM_DATA_SOURCE:    c.Bool("terraform-data-source"),
+		EKSCTL_NODE_GROUPS:       c.String("eksctl-node-groups"),
+		CLOUDFORMATION_PARAMETER: c.String("cloudformation-parameter"),
+		TEMPLATE:                 c.String("template"),
+		TEMPLATE_FILE:            c.String("template-file"),
+		TEMPLATE_SCOPE:           c.String("template-scope"),
+		OUTPUT_FORMAT:            c.String("output"),
+		MAX_RESULTS:              c.Int("max-results"),
+		AUTO_MODE:                c.Bool("auto-mode"),
+		INCLUDE_DEPRECATED:       c.Bool("include-deprecated"),
+		RECOMMENDED:              c.Bool("recommended"),
+		DEBUG_MODE:               c.Bool("debug"),
+	}
+}
+
+// expandSearchInputs expands regions, AMI types and Kubernetes versions of base into one input per search,
+// invalid queries are rejected before any request is sent.
+func expandSearchInputs(base amiSearchInputSpec) ([]amiSearchInputSpec, error) {
+	regions, err := parseRegions(base.AWS_REGION)
+	if err != nil {
+		return nil, err
+	}
+
+	// Empty AMI_TYPE expands to the default one based on AUTO_MODE
+	amiTypes, err := finder.ExpandAmiTypes(base.AMI_TYPE, base.AUTO_MODE)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := finder.ExpandKubernetesVersions(base.KUBERNETES_VERSION)
+	if err != nil {
+		return nil, err
+	}
+
+	type combination struct{ amiType, version string }
+	var combinations []combination
+	for _, amiType := range amiTypes {
+		for _, version := range versions {
+			combinations = append(combinations, combination{amiType, version})
+		}
 	}
+
+	// Invalid queries are rejected before any request is sent, unsupported AMI type and version
+	// combinations are skipped when looking up more than one of them
+	var supported []combination
+	for _, cb := range combinations {
+		q := toFinderQuery(base)
+		q.Region = regions[0]
+		q.AmiType = cb.amiType
+		q.KubernetesVersion = cb.version
+		if err := q.Validate(); err != nil {
+			if len(combinations) == 1 || (!errors.Is(err, finder.ErrUnsupportedVersion) && !errors.Is(err, finder.ErrInvalidReleaseDate) && !errors.Is(err, finder.ErrInvalidBottlerocketVersion)) {
+				return nil, err
+			}
+			fmt.Fprintf(os.Stderr, "Note: skipping %s with Kubernetes %s, %v\n", cb.amiType, cb.version, err)
+			continue
+		}
+		supported = append(supported, cb)
+	}
+	if len(supported) == 0 {
+		return nil, fmt.Errorf("none of the Kubernetes versions %s is supported by %s", strings.Join(versions, ", "), strings.Join(amiTypes, ", "))
+	}
+	combinations = supported
+
+	inputs := make([]amiSearchInputSpec, 0, len(regions)*len(combinations))
+	for _, region := range regions {
+		for _, cb := range combinations {
+			r := base
+			r.AWS_REGION = region
+			r.AMI_TYPE = cb.amiType
+			r.KUBERNETES_VERSION = cb.version
+			inputs = append(inputs, r)
+		}
+	}
+
+	return inputs, nil
 }
 
 func Wrapper(ctx context.Context, c *cli.Command) error {
 	ctx, cancel := context.WithTimeout(ctx, c.Duration("timeout"))
 	defer cancel()
 
-	r := amiSearchInput(c)
+	if err := applySharedFlags(c); err != nil {
+		return err
+	}
 
-	// Set default AMI_TYPE based on AUTO_MODE
-	if r.AMI_TYPE == "" {
-		if r.AUTO_MODE {
-			r.AMI_TYPE = "AUTO_MODE_STANDARD_x86_64"
-		} else {
-			r.AMI_TYPE = "AL2023_x86_64_STANDARD"
+	return searchAndRender(ctx, amiSearchInput(c))
+}
+
+// searchAndRender runs every search base expands to and renders the merged results,
+// partial failures are reported on stderr.
+func searchAndRender(ctx context.Context, base amiSearchInputSpec) error {
+	inputs, err := expandSearchInputs(base)
+	if err != nil {
+		return err
+	}
+
+	warnKubernetesLifecycle(inputs, time.Now())
+
+	// Errors are labeled with type and version only when looking up more than one of them
+	multiType := slices.ContainsFunc(inputs, func(i amiSearchInputSpec) bool { return i.AMI_TYPE != inputs[0].AMI_TYPE })
+	multiVersion := slices.ContainsFunc(inputs, func(i amiSearchInputSpec) bool { return i.KUBERNETES_VERSION != inputs[0].KUBERNETES_VERSION })
+
+	outcomes := multiRegionSearch(ctx, inputs)
+
+	var results []amiSearchResult
+	var errs []amiSearchError
+	for idx, o := range outcomes {
+		if o.Err != nil {
+			e := amiSearchError{Region: o.Region, Error: o.Err.Error()}
+			if multiType {
+				e.AmiType = inputs[idx].AMI_TYPE
+			}
+			if multiVersion {
+				e.KubernetesVersion = inputs[idx].KUBERNETES_VERSION
+			}
+			errs = append(errs, e)
+			continue
 		}
+		results = append(results, o.Results...)
 	}
 
-	// If region is specified but owner ID is missing or invalid, assume it is looking for EKS official image build
-	if len(r.AWS_REGION) > 0 && (len(r.AMI_OWNER_ID) == 0 || len(r.AMI_OWNER_ID) != 12) {
-		var mappings map[string]string
-		switch {
-		case strings.HasPrefix(r.AMI_TYPE, "AL2_"), strings.HasPrefix(r.AMI_TYPE, "AL2023_"):
-			r.AMI_OWNER_ID = constants.AwsAccountMappingsAL["*"]
-			mappings = constants.AwsAccountMappingsAL
-		case strings.HasPrefix(r.AMI_TYPE, "BOTTLEROCKET_"):
-			mappings = constants.AwsAccountMappingsBottlerocket
-		case strings.HasPrefix(r.AMI_TYPE, "WINDOWS_"):
-			mappings = constants.AwsAccountMappingsWindows
+	// Keep single search behavior unchanged, the error is returned as-is
+	if len(outcomes) == 1 && outcomes[0].Err != nil {
+		return outcomes[0].Err
+	}
+
+	if len(errs) == len(outcomes) {
+		for _, e := range errs {
+			fmt.Fprintf(os.Stderr, "[%s] %s\n", e.label(), e.Error)
 		}
-		if mappings != nil {
-			if v, ok := mappings[r.AWS_REGION]; ok {
-				r.AMI_OWNER_ID = v
-			}
+		return fmt.Errorf("all %d searches failed", len(outcomes))
+	}
+
+	if err := renderResults(os.Stdout, base, results, errs); err != nil {
+		return fmt.Errorf("unable to render results: %v", err)
+	}
+
+	// Partial failures are reported without aborting the whole run
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "Warning: [%s] %s\n", e.label(), e.Error)
+	}
+
+	return nil
+}
+
+// warnKubernetesLifecycle reports Kubernetes versions in extended support or past their end of life, once per version
+func warnKubernetesLifecycle(inputs []amiSearchInputSpec, now time.Time) {
+	var versions []string
+	for _, i := range inputs {
+		if !slices.Contains(versions, i.KUBERNETES_VERSION) {
+			versions = append(versions, i.KUBERNETES_VERSION)
 		}
 	}
 
-	return amiSearch(ctx, r)
+	for _, v := range versions {
+		if w := finder.KubernetesLifecycle(v, now).Warning(); w != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+		}
+	}
 }
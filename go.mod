@@ -8,8 +8,10 @@ require (
 	github.com/aws/aws-sdk-go-v2 v1.41.7
 	github.com/aws/aws-sdk-go-v2/config v1.32.17
 	github.com/aws/aws-sdk-go-v2/service/ec2 v1.302.0
+	github.com/aws/aws-sdk-go-v2/service/ssm v1.68.5
 	github.com/jedib0t/go-pretty/v6 v6.7.10
 	github.com/urfave/cli/v3 v3.9.0
+	go.yaml.in/yaml/v3 v3.0.5
 )
 
 require (
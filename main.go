@@ -31,6 +31,154 @@ func main() {
 			return cmd.Wrapper(ctx, c)
 		},
 		Commands: []*cli.Command{
+			{
+				Name:      "inspect",
+				Usage:     "Identify AMI type, Kubernetes version and release of the given AMI IDs",
+				ArgsUsage: "<ami-id> [<ami-id> ...]",
+				Action: func(ctx context.Context, c *cli.Command) error {
+					return cmd.Inspect(ctx, c)
+				},
+			},
+			{
+				Name:      "verify",
+				Usage:     "Verify the given AMI IDs are official Amazon EKS optimized AMIs, exits non-zero on mismatch",
+				ArgsUsage: "<ami-id> [<ami-id> ...]",
+				Action: func(ctx context.Context, c *cli.Command) error {
+					return cmd.Verify(ctx, c)
+				},
+			},
+			{
+				Name:  "owners",
+				Usage: "Manage official AMI owner mappings",
+				Commands: []*cli.Command{
+					{
+						Name:  "discover",
+						Usage: "Discover AMI owners through SSM public parameters and diff them against built-in mappings",
+						Flags: cmd.OwnersDiscoverFlags,
+						Action: func(ctx context.Context, c *cli.Command) error {
+							return cmd.OwnersDiscover(ctx, c)
+						},
+					},
+				},
+			},
+			{
+				Name:      "bump",
+				Usage:     "Rewrite AMI IDs in Terraform, eksctl and Karpenter files to the newest release of the same AMI type",
+				ArgsUsage: "[<path> ...]",
+				Flags:     cmd.BumpFlags,
+				Action: func(ctx context.Context, c *cli.Command) error {
+					return cmd.Bump(ctx, c)
+				},
+			},
+			{
+				Name:  "karpenter",
+				Usage: "Karpenter helpers, use --output karpenter or karpenter-alias to generate amiSelectorTerms",
+				Commands: []*cli.Command{
+					{
+						Name:      "resolve",
+						Usage:     "Resolve a Karpenter alias (e.g. al2023@v20260120) to concrete AMI IDs per region",
+						ArgsUsage: "<alias>",
+						Action: func(ctx context.Context, c *cli.Command) error {
+							return cmd.KarpenterResolve(ctx, c)
+						},
+					},
+				},
+			},
+			{
+				Name:  "lock",
+				Usage: "Pin the newest AMI of each region, AMI type and Kubernetes version into a lockfile",
+				Flags: cmd.LockFlags,
+				Action: func(ctx context.Context, c *cli.Command) error {
+					return cmd.Lock(ctx, c)
+				},
+				Commands: []*cli.Command{
+					{
+						Name:  "verify",
+						Usage: "Verify locked AMIs still exist, come from the official owner and are not deprecated",
+						Action: func(ctx context.Context, c *cli.Command) error {
+							return cmd.LockVerify(ctx, c)
+						},
+					},
+					{
+						Name:  "update",
+						Usage: "Bump locked AMIs to the newest release matching the same query and print a diff",
+						Action: func(ctx context.Context, c *cli.Command) error {
+							return cmd.LockUpdate(ctx, c)
+						},
+					},
+				},
+			},
+			{
+				Name:  "matrix",
+				Usage: "Print the Kubernetes versions each AMI type is published for",
+				Action: func(ctx context.Context, c *cli.Command) error {
+					return cmd.Matrix(ctx, c)
+				},
+			},
+			{
+				Name:  "types",
+				Usage: "Print the AMI types with their OS family, architecture, accelerator and owner mapping",
+				Action: func(ctx context.Context, c *cli.Command) error {
+					return cmd.Types(ctx, c)
+				},
+			},
+			{
+				Name:  "versions",
+				Usage: "Print the release, end of standard support and end of extended support dates of Amazon EKS versions",
+				Action: func(ctx context.Context, c *cli.Command) error {
+					return cmd.Versions(ctx, c)
+				},
+			},
+			{
+				Name:  "catalog",
+				Usage: "Bundle AMIs into a catalog file for lookups without access to AWS, use --catalog to search it",
+				Commands: []*cli.Command{
+					{
+						Name:  "export",
+						Usage: "Export every AMI matching the search flags, deprecated ones included, into a catalog signed with --signing-key",
+						Flags: cmd.CatalogExportFlags,
+						Action: func(ctx context.Context, c *cli.Command) error {
+							return cmd.CatalogExport(ctx, c)
+						},
+					},
+					{
+						Name:      "import",
+						Usage:     "Check a catalog, and its signature with --catalog-public-key, and install it for --offline lookups",
+						ArgsUsage: "<file>",
+						Action: func(ctx context.Context, c *cli.Command) error {
+							return cmd.CatalogImport(ctx, c)
+						},
+					},
+					{
+						Name:  "keygen",
+						Usage: "Generate the ed25519 key pair catalogs are signed with",
+						Flags: cmd.CatalogKeygenFlags,
+						Action: func(ctx context.Context, c *cli.Command) error {
+							return cmd.CatalogKeygen(ctx, c)
+						},
+					},
+				},
+			},
+			{
+				Name:  "cache",
+				Usage: "Manage the on-disk cache of DescribeImages results",
+				Commands: []*cli.Command{
+					{
+						Name:  "stats",
+						Usage: "Show entries, age and size of the cache",
+						Action: func(ctx context.Context, c *cli.Command) error {
+							return cmd.CacheStats(ctx, c)
+						},
+					},
+					{
+						Name:  "clear",
+						Usage: "Remove every cached entry",
+						Action: func(ctx context.Context, c *cli.Command) error {
+							return cmd.CacheClear(ctx, c)
+						},
+					},
+				},
+			},
 			{
 				Name:    "version",
 				Aliases: []string{"v"},
@@ -0,0 +1,257 @@
+// Package cache keeps DescribeImages results on disk between runs, so sweeps across many regions
+// don't call the EC2 API every time and searches could be served on hosts without network access.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+	"github.com/guessi/eks-ami-finder/pkg/finder"
+)
+
+// DefaultTTL is how long cached results are served before DescribeImages is called again
+const DefaultTTL = 15 * time.Minute
+
+// SchemaVersion is bumped whenever the entry layout changes, entries of other versions are ignored
+const SchemaVersion = "v1"
+
+const entryExt = ".json"
+
+// image is the part of types.Image the finder relies on
+type image struct {
+	ImageId         string `json:"imageId"`
+	Name            string `json:"name"`
+	Description     string `json:"description,omitempty"`
+	OwnerId         string `json:"ownerId"`
+	Architecture    string `json:"architecture"`
+	CreationDate    string `json:"creationDate"`
+	DeprecationTime string `json:"deprecationTime,omitempty"`
+}
+
+type entry struct {
+	SchemaVersion string          `json:"schemaVersion"`
+	Key           finder.CacheKey `json:"key"`
+	StoredAt      time.Time       `json:"storedAt"`
+	Images        []image         `json:"images"`
+}
+
+// Store is an ImageCache keeping one file per lookup in a directory
+type Store struct {
+	dir     string
+	ttl     time.Duration
+	offline bool
+	now     func() time.Time
+}
+
+// Dir returns the default cache directory, $XDG_CACHE_HOME/eks-ami-finder or the platform equivalent
+func Dir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to locate cache directory: %v", err)
+	}
+	return filepath.Join(base, constants.NAME), nil
+}
+
+// New returns a Store in dir, entries older than ttl are ignored unless offline is set
+func New(dir string, ttl time.Duration, offline bool) *Store {
+	return &Store{dir: dir, ttl: ttl, offline: offline, now: time.Now}
+}
+
+func (s *Store) path(key finder.CacheKey) string {
+	data, _ := json.Marshal(key)
+	sum := sha256.Sum256(data)
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+entryExt)
+}
+
+func (s *Store) fresh(storedAt time.Time) bool {
+	return s.now().Sub(storedAt) < s.ttl
+}
+
+// Get implements finder.ImageCache, a missing, stale or unreadable entry is reported as a miss
+func (s *Store) Get(key finder.CacheKey) ([]types.Image, time.Time, bool) {
+	e, err := readEntry(s.path(key))
+	if err != nil || e.Key != key {
+		return nil, time.Time{}, false
+	}
+	if !s.offline && !s.fresh(e.StoredAt) {
+		return nil, time.Time{}, false
+	}
+
+	images := make([]types.Image, 0, len(e.Images))
+	for _, i := range e.Images {
+		images = append(images, types.Image{
+			ImageId:         aws.String(i.ImageId),
+			Name:            aws.String(i.Name),
+			Description:     aws.String(i.Description),
+			OwnerId:         aws.String(i.OwnerId),
+			Architecture:    types.ArchitectureValues(i.Architecture),
+			CreationDate:    aws.String(i.CreationDate),
+			DeprecationTime: aws.String(i.DeprecationTime),
+		})
+	}
+	return images, e.StoredAt, true
+}
+
+// Put implements finder.ImageCache, the entry is written to a temporary file first so readers never see partial entries.
+// Offline stores don't write.
+func (s *Store) Put(key finder.CacheKey, images []types.Image) error {
+	// Offline lookups are served from elsewhere, e.g. a catalog, which must not pass for fresh results later
+	if s.offline {
+		return nil
+	}
+
+	e := entry{
+		SchemaVersion: SchemaVersion,
+		Key:           key,
+		StoredAt:      s.now().UTC(),
+		Images:        make([]image, 0, len(images)),
+	}
+	for _, i := range images {
+		e.Images = append(e.Images, image{
+			ImageId:         aws.ToString(i.ImageId),
+			Name:            aws.ToString(i.Name),
+			Description:     aws.ToString(i.Description),
+			OwnerId:         aws.ToString(i.OwnerId),
+			Architecture:    string(i.Architecture),
+			CreationDate:    aws.ToString(i.CreationDate),
+			DeprecationTime: aws.ToString(i.DeprecationTime),
+		})
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("unable to encode cache entry: %v", err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("unable to create cache directory: %v", err)
+	}
+	tmp, err := os.CreateTemp(s.dir, ".entry-*")
+	if err != nil {
+		return fmt.Errorf("unable to write cache entry: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("unable to write cache entry: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("unable to write cache entry: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(key)); err != nil {
+		return fmt.Errorf("unable to write cache entry: %v", err)
+	}
+	return nil
+}
+
+func readEntry(path string) (entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return entry{}, err
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return entry{}, err
+	}
+	if e.SchemaVersion != SchemaVersion {
+		return entry{}, fmt.Errorf("unsupported cache entry schemaVersion '%s'", e.SchemaVersion)
+	}
+	return e, nil
+}
+
+// entries lists the entry files in the cache directory, a missing directory has none
+func (s *Store) entries() ([]string, error) {
+	dirEntries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to read cache directory: %v", err)
+	}
+
+	var paths []string
+	for _, d := range dirEntries {
+		if d.Type().IsRegular() && strings.HasSuffix(d.Name(), entryExt) {
+			paths = append(paths, filepath.Join(s.dir, d.Name()))
+		}
+	}
+	return paths, nil
+}
+
+// Stats summarizes the content of the cache directory
+type Stats struct {
+	Dir     string
+	Entries int
+	Fresh   int // entries younger than the TTL
+	Stale   int // entries older than the TTL, still served offline
+	Invalid int // entries which could not be read, e.g. written by another version
+	Images  int
+	Bytes   int64
+	Oldest  time.Time
+	Newest  time.Time
+}
+
+// Stats walks through every entry of the cache directory
+func (s *Store) Stats() (Stats, error) {
+	st := Stats{Dir: s.dir}
+
+	paths, err := s.entries()
+	if err != nil {
+		return st, err
+	}
+
+	for _, path := range paths {
+		st.Entries++
+		if info, err := os.Stat(path); err == nil {
+			st.Bytes += info.Size()
+		}
+
+		e, err := readEntry(path)
+		if err != nil {
+			st.Invalid++
+			continue
+		}
+
+		if s.fresh(e.StoredAt) {
+			st.Fresh++
+		} else {
+			st.Stale++
+		}
+		st.Images += len(e.Images)
+		if st.Oldest.IsZero() || e.StoredAt.Before(st.Oldest) {
+			st.Oldest = e.StoredAt
+		}
+		if e.StoredAt.After(st.Newest) {
+			st.Newest = e.StoredAt
+		}
+	}
+
+	return st, nil
+}
+
+// Clear removes every entry of the cache directory and returns how many were removed
+func (s *Store) Clear() (int, error) {
+	paths, err := s.entries()
+	if err != nil {
+		return 0, err
+	}
+
+	for idx, path := range paths {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return idx, fmt.Errorf("unable to remove cache entry: %v", err)
+		}
+	}
+	return len(paths), nil
+}
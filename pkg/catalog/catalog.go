@@ -0,0 +1,303 @@
+// Package catalog bundles Amazon EKS optimized AMIs of chosen regions into a single checksummed file,
+// so searches could run where the EC2 API is unreachable, e.g. isolated partitions or air-gapped hosts.
+// The checksum only detects corrupted files, anyone editing a catalog could recompute it. Where catalogs
+// come from is proven by a detached ed25519 signature, see Sign and LoadSigned.
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/guessi/eks-ami-finder/pkg/finder"
+)
+
+// DefaultPath is the bundle written by export when none is specified
+const DefaultPath = "eks-ami-catalog.json"
+
+// SchemaVersion is only bumped on breaking changes of the bundle layout
+const SchemaVersion = "v1"
+
+const checksumPrefix = "sha256:"
+
+// Image is an AMI of a region as returned by DescribeImages
+type Image struct {
+	Region          string `json:"region"`
+	ImageId         string `json:"imageId"`
+	Name            string `json:"name"`
+	Description     string `json:"description,omitempty"`
+	OwnerId         string `json:"ownerId"`
+	Architecture    string `json:"architecture"`
+	CreationDate    string `json:"creationDate"`
+	DeprecationTime string `json:"deprecationTime,omitempty"`
+}
+
+// Bundle is the content of a catalog file
+type Bundle struct {
+	SchemaVersion string    `json:"schemaVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Checksum      string    `json:"checksum"` // sha256 of the bundle encoded with an empty checksum, integrity only
+	Images        []Image   `json:"images"`
+
+	mu     sync.Mutex
+	seen   map[string]struct{} // region and ID of the images, built on the first add
+	signed bool                // the signature was checked on load
+}
+
+// New returns an empty bundle
+func New() *Bundle {
+	return &Bundle{SchemaVersion: SchemaVersion, Images: []Image{}}
+}
+
+// Load reads the bundle at path and rejects it when the checksum doesn't match its content,
+// the signature is not checked, see LoadSigned
+func Load(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read catalog: %v", err)
+	}
+	return parse(path, data)
+}
+
+// parse decodes the content of the catalog file at path and checks its checksum
+func parse(path string, data []byte) (*Bundle, error) {
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("unable to parse catalog %s: %v", path, err)
+	}
+	if b.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("unsupported catalog schemaVersion '%s' in %s, expected %s", b.SchemaVersion, path, SchemaVersion)
+	}
+
+	sum, err := b.checksum()
+	if err != nil {
+		return nil, err
+	}
+	if b.Checksum != sum {
+		return nil, fmt.Errorf("checksum mismatch in catalog %s, the file is corrupted or incomplete", path)
+	}
+
+	return &b, nil
+}
+
+// Save writes the bundle to path with images in a stable order and a fresh checksum
+func (b *Bundle) Save(path string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.SchemaVersion = SchemaVersion
+	if b.CreatedAt.IsZero() {
+		b.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	}
+	slices.SortFunc(b.Images, func(a, c Image) int {
+		if n := strings.Compare(a.Region, c.Region); n != 0 {
+			return n
+		}
+		return strings.Compare(a.ImageId, c.ImageId)
+	})
+
+	sum, err := b.checksum()
+	if err != nil {
+		return err
+	}
+	b.Checksum = sum
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode catalog: %v", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("unable to write catalog %s: %v", path, err)
+	}
+	return nil
+}
+
+func (b *Bundle) checksum() (string, error) {
+	unsigned := Bundle{
+		SchemaVersion: b.SchemaVersion,
+		CreatedAt:     b.CreatedAt,
+		Images:        b.Images,
+	}
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode catalog: %v", err)
+	}
+	sum := sha256.Sum256(data)
+	return checksumPrefix + hex.EncodeToString(sum[:]), nil
+}
+
+// Regions returns the regions covered by the bundle
+func (b *Bundle) Regions() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var regions []string
+	for _, i := range b.Images {
+		if !slices.Contains(regions, i.Region) {
+			regions = append(regions, i.Region)
+		}
+	}
+	slices.Sort(regions)
+	return regions
+}
+
+// Len returns the number of images in the bundle
+func (b *Bundle) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.Images)
+}
+
+// add stores the images of a region, images already in the bundle are skipped
+func (b *Bundle) add(region string, images []types.Image) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.seen == nil {
+		b.seen = make(map[string]struct{}, len(b.Images))
+		for _, i := range b.Images {
+			b.seen[i.Region+"/"+i.ImageId] = struct{}{}
+		}
+	}
+
+	for _, i := range images {
+		id := aws.ToString(i.ImageId)
+		key := region + "/" + id
+		if _, ok := b.seen[key]; ok {
+			continue
+		}
+		b.seen[key] = struct{}{}
+		b.Images = append(b.Images, Image{
+			Region:          region,
+			ImageId:         id,
+			Name:            aws.ToString(i.Name),
+			Description:     aws.ToString(i.Description),
+			OwnerId:         aws.ToString(i.OwnerId),
+			Architecture:    string(i.Architecture),
+			CreationDate:    aws.ToString(i.CreationDate),
+			DeprecationTime: aws.ToString(i.DeprecationTime),
+		})
+	}
+}
+
+// recorder passes DescribeImages calls through and adds every image returned to the bundle
+type recorder struct {
+	bundle *Bundle
+	region string
+	client ec2.DescribeImagesAPIClient
+}
+
+func (r *recorder) DescribeImages(ctx context.Context, input *ec2.DescribeImagesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error) {
+	out, err := r.client.DescribeImages(ctx, input, optFns...)
+	if err == nil {
+		r.bundle.add(r.region, out.Images)
+	}
+	return out, err
+}
+
+// Record wraps the EC2 client of a region, so every image looked up through it ends up in the bundle
+func (b *Bundle) Record(region string, client ec2.DescribeImagesAPIClient) ec2.DescribeImagesAPIClient {
+	return &recorder{bundle: b, region: region, client: client}
+}
+
+// client answers DescribeImages from the images of a region in the bundle
+type client struct {
+	bundle *Bundle
+	region string
+	now    func() time.Time
+}
+
+// Client returns a DescribeImages client serving the images of a region from the bundle.
+// Filters on image-id, owner-id and name are supported, with the same wildcards as EC2.
+func (b *Bundle) Client(region string) ec2.DescribeImagesAPIClient {
+	return &client{bundle: b, region: region, now: time.Now}
+}
+
+func (c *client) DescribeImages(ctx context.Context, input *ec2.DescribeImagesInput, _ ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error) {
+	var matchers []func(Image) bool
+	if len(input.ImageIds) > 0 {
+		matchers = append(matchers, func(i Image) bool { return slices.Contains(input.ImageIds, i.ImageId) })
+	}
+	for _, f := range input.Filters {
+		var field func(Image) string
+		switch aws.ToString(f.Name) {
+		case "image-id":
+			field = func(i Image) string { return i.ImageId }
+		case "owner-id":
+			field = func(i Image) string { return i.OwnerId }
+		case "name":
+			field = func(i Image) string { return i.Name }
+		default:
+			return nil, fmt.Errorf("filter '%s' is not supported by catalogs", aws.ToString(f.Name))
+		}
+
+		values := make([]*regexp.Regexp, 0, len(f.Values))
+		for _, v := range f.Values {
+			values = append(values, wildcardRegexp(v))
+		}
+		matchers = append(matchers, func(i Image) bool {
+			return slices.ContainsFunc(values, func(re *regexp.Regexp) bool { return re.MatchString(field(i)) })
+		})
+	}
+
+	// Same as EC2, deprecated images are left out unless asked for or looked up by ID
+	now := c.now().UTC().Format(finder.TimeLayout)
+	includeDeprecated := aws.ToBool(input.IncludeDeprecated) || len(input.ImageIds) > 0
+
+	c.bundle.mu.Lock()
+	defer c.bundle.mu.Unlock()
+
+	out := &ec2.DescribeImagesOutput{Images: []types.Image{}}
+	for _, i := range c.bundle.Images {
+		if i.Region != c.region {
+			continue
+		}
+		if !includeDeprecated && i.DeprecationTime != "" && i.DeprecationTime <= now {
+			continue
+		}
+		if slices.ContainsFunc(matchers, func(match func(Image) bool) bool { return !match(i) }) {
+			continue
+		}
+		out.Images = append(out.Images, types.Image{
+			ImageId:         aws.String(i.ImageId),
+			Name:            aws.String(i.Name),
+			Description:     aws.String(i.Description),
+			OwnerId:         aws.String(i.OwnerId),
+			Architecture:    types.ArchitectureValues(i.Architecture),
+			CreationDate:    aws.String(i.CreationDate),
+			DeprecationTime: aws.String(i.DeprecationTime),
+		})
+	}
+
+	return out, nil
+}
+
+// wildcardRegexp turns an EC2 filter value into an anchored regular expression, * and ? are wildcards
+func wildcardRegexp(v string) *regexp.Regexp {
+	var expr bytes.Buffer
+	expr.WriteString("^")
+	for _, r := range v {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
@@ -0,0 +1,48 @@
+package catalog
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func testImages(ids ...string) []types.Image {
+	images := make([]types.Image, 0, len(ids))
+	for _, id := range ids {
+		images = append(images, types.Image{ImageId: aws.String(id)})
+	}
+	return images
+}
+
+func TestAddSkipsDuplicatesPerRegion(t *testing.T) {
+	b := New()
+	b.add("us-east-1", testImages("ami-01", "ami-02"))
+	b.add("us-east-1", testImages("ami-02", "ami-03", "ami-03"))
+	b.add("eu-west-1", testImages("ami-01"))
+
+	if got, want := b.Len(), 4; got != want {
+		t.Errorf("Len() = %d, want %d", got, want)
+	}
+}
+
+func TestAddAfterLoadSkipsSavedImages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultPath)
+
+	b := New()
+	b.add("us-east-1", testImages("ami-01", "ami-02"))
+	if err := b.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	loaded.add("us-east-1", testImages("ami-02", "ami-03"))
+
+	if got, want := loaded.Len(), 3; got != want {
+		t.Errorf("Len() = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,156 @@
+package catalog
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SignatureExt is appended to the catalog path to get its detached signature
+const SignatureExt = ".sig"
+
+const (
+	pemTypePublicKey  = "PUBLIC KEY"
+	pemTypePrivateKey = "PRIVATE KEY"
+)
+
+// SignaturePath returns where the detached signature of the catalog at path is kept
+func SignaturePath(path string) string {
+	return path + SignatureExt
+}
+
+// GenerateKey returns a new ed25519 key pair, PEM encoded as PKIX public key and PKCS #8 private key
+func GenerateKey() (publicKey, privateKey []byte, err error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to generate key: %v", err)
+	}
+
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to encode public key: %v", err)
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to encode private key: %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypePublicKey, Bytes: pubDER}),
+		pem.EncodeToMemory(&pem.Block{Type: pemTypePrivateKey, Bytes: privDER}),
+		nil
+}
+
+func readPEM(path, pemType string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read key: %v", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemType {
+		return nil, fmt.Errorf("%s is not a PEM encoded %s", path, strings.ToLower(pemType))
+	}
+	return block.Bytes, nil
+}
+
+// LoadPublicKey reads a PEM encoded ed25519 public key, as written by GenerateKey
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	der, err := readPEM(path, pemTypePublicKey)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse public key %s: %v", path, err)
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not an ed25519 key", path)
+	}
+	return pub, nil
+}
+
+// LoadPrivateKey reads a PEM encoded ed25519 private key, as written by GenerateKey
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	der, err := readPEM(path, pemTypePrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse private key %s: %v", path, err)
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %s is not an ed25519 key", path)
+	}
+	return priv, nil
+}
+
+// Sign writes the detached signature of the catalog file at path next to it, see SignaturePath
+func Sign(path string, key ed25519.PrivateKey) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read catalog: %v", err)
+	}
+
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+	if err := os.WriteFile(SignaturePath(path), []byte(sig+"\n"), 0o644); err != nil {
+		return fmt.Errorf("unable to write catalog signature: %v", err)
+	}
+	return nil
+}
+
+// VerifySignature checks the detached signature of the catalog file at path against the public key
+func VerifySignature(path string, key ed25519.PublicKey) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read catalog: %v", err)
+	}
+	return verifySignature(path, data, key)
+}
+
+// verifySignature checks the detached signature of the catalog file at path over data, its content
+func verifySignature(path string, data []byte, key ed25519.PublicKey) error {
+	encoded, err := os.ReadFile(SignaturePath(path))
+	if err != nil {
+		return fmt.Errorf("unable to read catalog signature: %v", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return fmt.Errorf("invalid catalog signature %s: %v", SignaturePath(path), err)
+	}
+
+	if !ed25519.Verify(key, data, sig) {
+		return fmt.Errorf("signature of catalog %s doesn't match the public key, the file was not signed with it or was modified", path)
+	}
+	return nil
+}
+
+// LoadSigned checks the signature of the catalog file at path before loading it, the bundle is reported as Signed.
+// The file is read once, so the content loaded is the one the signature was checked against.
+func LoadSigned(path string, key ed25519.PublicKey) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read catalog: %v", err)
+	}
+	if err := verifySignature(path, data, key); err != nil {
+		return nil, err
+	}
+
+	b, err := parse(path, data)
+	if err != nil {
+		return nil, err
+	}
+	b.signed = true
+	return b, nil
+}
+
+// Signed reports whether the bundle was loaded with its signature checked, see LoadSigned
+func (b *Bundle) Signed() bool {
+	return b.signed
+}
@@ -0,0 +1,146 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func writeKeys(t *testing.T, dir, name string) (publicPath, privatePath string) {
+	t.Helper()
+	pub, priv, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	publicPath = filepath.Join(dir, name+".pub")
+	privatePath = filepath.Join(dir, name+".key")
+	if err := os.WriteFile(publicPath, pub, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(privatePath, priv, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return publicPath, privatePath
+}
+
+func writeSignedCatalog(t *testing.T, dir, privatePath string) string {
+	t.Helper()
+	b := New()
+	b.add("us-east-1", []types.Image{{
+		ImageId:      aws.String("ami-0123456789abcdef0"),
+		Name:         aws.String("amazon-eks-node-al2023-x86_64-standard-1.35-v20260120"),
+		OwnerId:      aws.String("602401143452"),
+		CreationDate: aws.String("2026-01-20T00:00:00.000Z"),
+	}})
+
+	path := filepath.Join(dir, DefaultPath)
+	if err := b.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	key, err := LoadPrivateKey(privatePath)
+	if err != nil {
+		t.Fatalf("LoadPrivateKey() error = %v", err)
+	}
+	if err := Sign(path, key); err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	return path
+}
+
+func TestLoadSigned(t *testing.T) {
+	dir := t.TempDir()
+	publicPath, privatePath := writeKeys(t, dir, "trusted")
+	path := writeSignedCatalog(t, dir, privatePath)
+
+	key, err := LoadPublicKey(publicPath)
+	if err != nil {
+		t.Fatalf("LoadPublicKey() error = %v", err)
+	}
+	b, err := LoadSigned(path, key)
+	if err != nil {
+		t.Fatalf("LoadSigned() error = %v", err)
+	}
+	if !b.Signed() || b.Len() != 1 {
+		t.Errorf("LoadSigned() = signed %t with %d images, want signed with 1 image", b.Signed(), b.Len())
+	}
+
+	unsigned, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if unsigned.Signed() {
+		t.Error("Load() reported a bundle as signed without checking its signature")
+	}
+}
+
+func TestLoadSignedRejectsTampering(t *testing.T) {
+	dir := t.TempDir()
+	publicPath, privatePath := writeKeys(t, dir, "trusted")
+	_, otherPrivatePath := writeKeys(t, dir, "other")
+	key, err := LoadPublicKey(publicPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("content replaced with a recomputed checksum", func(t *testing.T) {
+		path := writeSignedCatalog(t, t.TempDir(), privatePath)
+		b, err := Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Images[0].OwnerId = "111122223333"
+		if err := b.Save(path); err != nil {
+			t.Fatal(err)
+		}
+		// The checksum alone doesn't notice, Save recomputed it
+		if _, err := Load(path); err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if _, err := LoadSigned(path, key); err == nil {
+			t.Error("LoadSigned() accepted a modified catalog")
+		}
+	})
+
+	t.Run("signed with another key", func(t *testing.T) {
+		path := writeSignedCatalog(t, t.TempDir(), otherPrivatePath)
+		if _, err := LoadSigned(path, key); err == nil || !strings.Contains(err.Error(), "doesn't match the public key") {
+			t.Errorf("LoadSigned() error = %v, want a signature mismatch", err)
+		}
+	})
+
+	t.Run("missing signature", func(t *testing.T) {
+		path := writeSignedCatalog(t, t.TempDir(), privatePath)
+		if err := os.Remove(SignaturePath(path)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadSigned(path, key); err == nil {
+			t.Error("LoadSigned() accepted a catalog without signature")
+		}
+	})
+
+	t.Run("garbled signature", func(t *testing.T) {
+		path := writeSignedCatalog(t, t.TempDir(), privatePath)
+		if err := os.WriteFile(SignaturePath(path), []byte("not base64!\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadSigned(path, key); err == nil {
+			t.Error("LoadSigned() accepted a garbled signature")
+		}
+	})
+}
+
+func TestLoadKeysRejectWrongType(t *testing.T) {
+	dir := t.TempDir()
+	publicPath, privatePath := writeKeys(t, dir, "trusted")
+
+	if _, err := LoadPublicKey(privatePath); err == nil {
+		t.Error("LoadPublicKey() accepted a private key")
+	}
+	if _, err := LoadPrivateKey(publicPath); err == nil {
+		t.Error("LoadPrivateKey() accepted a public key")
+	}
+}
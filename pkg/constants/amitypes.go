@@ -0,0 +1,392 @@
+package constants
+
+// OS families of AMI types
+const (
+	OSFamilyAL2          = "AL2"
+	OSFamilyAL2023       = "AL2023"
+	OSFamilyBottlerocket = "Bottlerocket"
+	OSFamilyWindows      = "Windows"
+)
+
+// Architectures of AMI types
+const (
+	ArchitectureX86_64 = "x86_64"
+	ArchitectureARM64  = "arm64"
+)
+
+// Accelerators of AMI types, standard AMI types have none
+const (
+	AcceleratorNVIDIA = "NVIDIA"
+	AcceleratorNeuron = "Neuron"
+)
+
+// Owner mapping tables, see AwsAccountMappings*
+const (
+	OwnerFamilyAmazonLinux  = "AmazonLinux"
+	OwnerFamilyBottlerocket = "Bottlerocket"
+	OwnerFamilyWindows      = "Windows"
+	OwnerFamilyAutoMode     = "AutoMode"
+)
+
+// amiFamily values of eksctl node groups
+// - https://eksctl.io/usage/schema/#managedNodeGroups-amiFamily
+const (
+	EksctlFamilyAL2             = "AmazonLinux2"
+	EksctlFamilyAL2023          = "AmazonLinux2023"
+	EksctlFamilyBottlerocket    = "Bottlerocket"
+	EksctlFamilyWindows2019Core = "WindowsServer2019CoreContainer"
+	EksctlFamilyWindows2019Full = "WindowsServer2019FullContainer"
+	EksctlFamilyWindows2022Core = "WindowsServer2022CoreContainer"
+	EksctlFamilyWindows2022Full = "WindowsServer2022FullContainer"
+)
+
+// amiFamily values of Karpenter EC2NodeClasses
+// - https://karpenter.sh/docs/concepts/nodeclasses/#specamifamily
+const (
+	KarpenterFamilyAL2          = "AL2"
+	KarpenterFamilyAL2023       = "AL2023"
+	KarpenterFamilyBottlerocket = "Bottlerocket"
+	KarpenterFamilyWindows2019  = "Windows2019"
+	KarpenterFamilyWindows2022  = "Windows2022"
+)
+
+// AmiType describes an AMI type and how its AMIs are looked up
+type AmiType struct {
+	Name          string
+	AutoMode      bool
+	OSFamily      string
+	Architecture  string
+	Accelerator   string // empty for standard AMI types
+	FIPS          bool
+	OwnerFamily   string // owner mapping table the official owner is resolved from
+	NamePattern   string // DescribeImages name filter, formatted with the Kubernetes version then the release date
+	ReleaseFilter bool   // whether the release date is part of the name, Bottlerocket names carry the OS release instead
+
+	EksctlFamily    string // amiFamily of eksctl node groups, empty when eksctl has none
+	KarpenterFamily string // amiFamily of Karpenter EC2NodeClasses, empty when Karpenter has none
+	KarpenterAlias  bool   // whether Karpenter selects it by alias, FIPS and Windows Full variants are selected by ID only
+}
+
+// AmiTypes is the registry of AMI types, Auto Mode ones are only valid in Auto Mode
+// - https://docs.aws.amazon.com/eks/latest/APIReference/API_Nodegroup.html#AmazonEKS-Type-Nodegroup-amiType
+var AmiTypes = []AmiType{
+	{
+		Name:            "AL2_ARM_64",
+		OSFamily:        OSFamilyAL2,
+		Architecture:    ArchitectureARM64,
+		OwnerFamily:     OwnerFamilyAmazonLinux,
+		NamePattern:     "amazon-eks-arm64-node-%s-v%s*",
+		ReleaseFilter:   true,
+		EksctlFamily:    EksctlFamilyAL2,
+		KarpenterFamily: KarpenterFamilyAL2,
+		KarpenterAlias:  true,
+	},
+	{
+		Name:            "AL2_x86_64",
+		OSFamily:        OSFamilyAL2,
+		Architecture:    ArchitectureX86_64,
+		OwnerFamily:     OwnerFamilyAmazonLinux,
+		NamePattern:     "amazon-eks-node-%s-v%s*",
+		ReleaseFilter:   true,
+		EksctlFamily:    EksctlFamilyAL2,
+		KarpenterFamily: KarpenterFamilyAL2,
+		KarpenterAlias:  true,
+	},
+	{
+		Name:            "AL2_x86_64_GPU",
+		OSFamily:        OSFamilyAL2,
+		Architecture:    ArchitectureX86_64,
+		Accelerator:     AcceleratorNVIDIA,
+		OwnerFamily:     OwnerFamilyAmazonLinux,
+		NamePattern:     "amazon-eks-gpu-node-%s-v%s*",
+		ReleaseFilter:   true,
+		EksctlFamily:    EksctlFamilyAL2,
+		KarpenterFamily: KarpenterFamilyAL2,
+		KarpenterAlias:  true,
+	},
+	{
+		Name:            "AL2023_ARM_64_NVIDIA",
+		OSFamily:        OSFamilyAL2023,
+		Architecture:    ArchitectureARM64,
+		Accelerator:     AcceleratorNVIDIA,
+		OwnerFamily:     OwnerFamilyAmazonLinux,
+		NamePattern:     "amazon-eks-node-al2023-arm64-nvidia-%s-v%s*",
+		ReleaseFilter:   true,
+		EksctlFamily:    EksctlFamilyAL2023,
+		KarpenterFamily: KarpenterFamilyAL2023,
+		KarpenterAlias:  true,
+	},
+	{
+		Name:            "AL2023_ARM_64_STANDARD",
+		OSFamily:        OSFamilyAL2023,
+		Architecture:    ArchitectureARM64,
+		OwnerFamily:     OwnerFamilyAmazonLinux,
+		NamePattern:     "amazon-eks-node-al2023-arm64-standard-%s-v%s*",
+		ReleaseFilter:   true,
+		EksctlFamily:    EksctlFamilyAL2023,
+		KarpenterFamily: KarpenterFamilyAL2023,
+		KarpenterAlias:  true,
+	},
+	{
+		Name:            "AL2023_x86_64_NEURON",
+		OSFamily:        OSFamilyAL2023,
+		Architecture:    ArchitectureX86_64,
+		Accelerator:     AcceleratorNeuron,
+		OwnerFamily:     OwnerFamilyAmazonLinux,
+		NamePattern:     "amazon-eks-node-al2023-x86_64-neuron-%s-v%s*",
+		ReleaseFilter:   true,
+		EksctlFamily:    EksctlFamilyAL2023,
+		KarpenterFamily: KarpenterFamilyAL2023,
+		KarpenterAlias:  true,
+	},
+	{
+		Name:            "AL2023_x86_64_NVIDIA",
+		OSFamily:        OSFamilyAL2023,
+		Architecture:    ArchitectureX86_64,
+		Accelerator:     AcceleratorNVIDIA,
+		OwnerFamily:     OwnerFamilyAmazonLinux,
+		NamePattern:     "amazon-eks-node-al2023-x86_64-nvidia-%s-v%s*",
+		ReleaseFilter:   true,
+		EksctlFamily:    EksctlFamilyAL2023,
+		KarpenterFamily: KarpenterFamilyAL2023,
+		KarpenterAlias:  true,
+	},
+	{
+		Name:            "AL2023_x86_64_STANDARD",
+		OSFamily:        OSFamilyAL2023,
+		Architecture:    ArchitectureX86_64,
+		OwnerFamily:     OwnerFamilyAmazonLinux,
+		NamePattern:     "amazon-eks-node-al2023-x86_64-standard-%s-v%s*",
+		ReleaseFilter:   true,
+		EksctlFamily:    EksctlFamilyAL2023,
+		KarpenterFamily: KarpenterFamilyAL2023,
+		KarpenterAlias:  true,
+	},
+	{
+		Name:            "BOTTLEROCKET_ARM_64",
+		OSFamily:        OSFamilyBottlerocket,
+		Architecture:    ArchitectureARM64,
+		OwnerFamily:     OwnerFamilyBottlerocket,
+		NamePattern:     "bottlerocket-aws-k8s-%s-aarch64-v*",
+		EksctlFamily:    EksctlFamilyBottlerocket,
+		KarpenterFamily: KarpenterFamilyBottlerocket,
+		KarpenterAlias:  true,
+	},
+	{
+		Name:            "BOTTLEROCKET_ARM_64_FIPS",
+		OSFamily:        OSFamilyBottlerocket,
+		Architecture:    ArchitectureARM64,
+		FIPS:            true,
+		OwnerFamily:     OwnerFamilyBottlerocket,
+		NamePattern:     "bottlerocket-aws-k8s-%s-fips-aarch64-v*",
+		EksctlFamily:    EksctlFamilyBottlerocket,
+		KarpenterFamily: KarpenterFamilyBottlerocket,
+	},
+	{
+		Name:            "BOTTLEROCKET_ARM_64_NVIDIA",
+		OSFamily:        OSFamilyBottlerocket,
+		Architecture:    ArchitectureARM64,
+		Accelerator:     AcceleratorNVIDIA,
+		OwnerFamily:     OwnerFamilyBottlerocket,
+		NamePattern:     "bottlerocket-aws-k8s-%s-nvidia-aarch64-v*",
+		EksctlFamily:    EksctlFamilyBottlerocket,
+		KarpenterFamily: KarpenterFamilyBottlerocket,
+		KarpenterAlias:  true,
+	},
+	{
+		Name:            "BOTTLEROCKET_ARM_64_NVIDIA_FIPS",
+		OSFamily:        OSFamilyBottlerocket,
+		Architecture:    ArchitectureARM64,
+		Accelerator:     AcceleratorNVIDIA,
+		FIPS:            true,
+		OwnerFamily:     OwnerFamilyBottlerocket,
+		NamePattern:     "bottlerocket-aws-k8s-%s-nvidia-fips-aarch64-v*",
+		EksctlFamily:    EksctlFamilyBottlerocket,
+		KarpenterFamily: KarpenterFamilyBottlerocket,
+	},
+	{
+		Name:            "BOTTLEROCKET_x86_64",
+		OSFamily:        OSFamilyBottlerocket,
+		Architecture:    ArchitectureX86_64,
+		OwnerFamily:     OwnerFamilyBottlerocket,
+		NamePattern:     "bottlerocket-aws-k8s-%s-x86_64-v*",
+		EksctlFamily:    EksctlFamilyBottlerocket,
+		KarpenterFamily: KarpenterFamilyBottlerocket,
+		KarpenterAlias:  true,
+	},
+	{
+		Name:            "BOTTLEROCKET_x86_64_FIPS",
+		OSFamily:        OSFamilyBottlerocket,
+		Architecture:    ArchitectureX86_64,
+		FIPS:            true,
+		OwnerFamily:     OwnerFamilyBottlerocket,
+		NamePattern:     "bottlerocket-aws-k8s-%s-fips-x86_64-v*",
+		EksctlFamily:    EksctlFamilyBottlerocket,
+		KarpenterFamily: KarpenterFamilyBottlerocket,
+	},
+	{
+		Name:            "BOTTLEROCKET_x86_64_NVIDIA",
+		OSFamily:        OSFamilyBottlerocket,
+		Architecture:    ArchitectureX86_64,
+		Accelerator:     AcceleratorNVIDIA,
+		OwnerFamily:     OwnerFamilyBottlerocket,
+		NamePattern:     "bottlerocket-aws-k8s-%s-nvidia-x86_64-v*",
+		EksctlFamily:    EksctlFamilyBottlerocket,
+		KarpenterFamily: KarpenterFamilyBottlerocket,
+		KarpenterAlias:  true,
+	},
+	{
+		Name:            "BOTTLEROCKET_x86_64_NVIDIA_FIPS",
+		OSFamily:        OSFamilyBottlerocket,
+		Architecture:    ArchitectureX86_64,
+		Accelerator:     AcceleratorNVIDIA,
+		FIPS:            true,
+		OwnerFamily:     OwnerFamilyBottlerocket,
+		NamePattern:     "bottlerocket-aws-k8s-%s-nvidia-fips-x86_64-v*",
+		EksctlFamily:    EksctlFamilyBottlerocket,
+		KarpenterFamily: KarpenterFamilyBottlerocket,
+	},
+	{
+		Name:          "WINDOWS_CORE_2016_x86_64",
+		OSFamily:      OSFamilyWindows,
+		Architecture:  ArchitectureX86_64,
+		OwnerFamily:   OwnerFamilyWindows,
+		NamePattern:   "Windows_Server-2016-English-Core-EKS_Optimized-%s-%s*",
+		ReleaseFilter: true,
+	},
+	{
+		Name:            "WINDOWS_CORE_2019_x86_64",
+		OSFamily:        OSFamilyWindows,
+		Architecture:    ArchitectureX86_64,
+		OwnerFamily:     OwnerFamilyWindows,
+		NamePattern:     "Windows_Server-2019-English-Core-EKS_Optimized-%s-%s*",
+		ReleaseFilter:   true,
+		EksctlFamily:    EksctlFamilyWindows2019Core,
+		KarpenterFamily: KarpenterFamilyWindows2019,
+		KarpenterAlias:  true,
+	},
+	{
+		Name:            "WINDOWS_CORE_2022_x86_64",
+		OSFamily:        OSFamilyWindows,
+		Architecture:    ArchitectureX86_64,
+		OwnerFamily:     OwnerFamilyWindows,
+		NamePattern:     "Windows_Server-2022-English-Core-EKS_Optimized-%s-%s*",
+		ReleaseFilter:   true,
+		EksctlFamily:    EksctlFamilyWindows2022Core,
+		KarpenterFamily: KarpenterFamilyWindows2022,
+		KarpenterAlias:  true,
+	},
+	{
+		Name:          "WINDOWS_CORE_2025_x86_64",
+		OSFamily:      OSFamilyWindows,
+		Architecture:  ArchitectureX86_64,
+		OwnerFamily:   OwnerFamilyWindows,
+		NamePattern:   "Windows_Server-2025-English-Core-EKS_Optimized-%s-%s*",
+		ReleaseFilter: true,
+	},
+	{
+		Name:          "WINDOWS_FULL_2016_x86_64",
+		OSFamily:      OSFamilyWindows,
+		Architecture:  ArchitectureX86_64,
+		OwnerFamily:   OwnerFamilyWindows,
+		NamePattern:   "Windows_Server-2016-English-Full-EKS_Optimized-%s-%s*",
+		ReleaseFilter: true,
+	},
+	{
+		Name:            "WINDOWS_FULL_2019_x86_64",
+		OSFamily:        OSFamilyWindows,
+		Architecture:    ArchitectureX86_64,
+		OwnerFamily:     OwnerFamilyWindows,
+		NamePattern:     "Windows_Server-2019-English-Full-EKS_Optimized-%s-%s*",
+		ReleaseFilter:   true,
+		EksctlFamily:    EksctlFamilyWindows2019Full,
+		KarpenterFamily: KarpenterFamilyWindows2019,
+	},
+	{
+		Name:            "WINDOWS_FULL_2022_x86_64",
+		OSFamily:        OSFamilyWindows,
+		Architecture:    ArchitectureX86_64,
+		OwnerFamily:     OwnerFamilyWindows,
+		NamePattern:     "Windows_Server-2022-English-Full-EKS_Optimized-%s-%s*",
+		ReleaseFilter:   true,
+		EksctlFamily:    EksctlFamilyWindows2022Full,
+		KarpenterFamily: KarpenterFamilyWindows2022,
+	},
+	{
+		Name:          "WINDOWS_FULL_2025_x86_64",
+		OSFamily:      OSFamilyWindows,
+		Architecture:  ArchitectureX86_64,
+		OwnerFamily:   OwnerFamilyWindows,
+		NamePattern:   "Windows_Server-2025-English-Full-EKS_Optimized-%s-%s*",
+		ReleaseFilter: true,
+	},
+	{
+		Name:          "AUTO_MODE_NEURON_x86_64",
+		AutoMode:      true,
+		OSFamily:      OSFamilyBottlerocket,
+		Architecture:  ArchitectureX86_64,
+		Accelerator:   AcceleratorNeuron,
+		OwnerFamily:   OwnerFamilyAutoMode,
+		NamePattern:   "eks-auto-neuron-%s-x86_64-%s*",
+		ReleaseFilter: true,
+	},
+	{
+		Name:          "AUTO_MODE_NVIDIA_ARM_64",
+		AutoMode:      true,
+		OSFamily:      OSFamilyBottlerocket,
+		Architecture:  ArchitectureARM64,
+		Accelerator:   AcceleratorNVIDIA,
+		OwnerFamily:   OwnerFamilyAutoMode,
+		NamePattern:   "eks-auto-nvidia-%s-aarch64-%s*",
+		ReleaseFilter: true,
+	},
+	{
+		Name:          "AUTO_MODE_NVIDIA_x86_64",
+		AutoMode:      true,
+		OSFamily:      OSFamilyBottlerocket,
+		Architecture:  ArchitectureX86_64,
+		Accelerator:   AcceleratorNVIDIA,
+		OwnerFamily:   OwnerFamilyAutoMode,
+		NamePattern:   "eks-auto-nvidia-%s-x86_64-%s*",
+		ReleaseFilter: true,
+	},
+	{
+		Name:          "AUTO_MODE_STANDARD_ARM_64",
+		AutoMode:      true,
+		OSFamily:      OSFamilyBottlerocket,
+		Architecture:  ArchitectureARM64,
+		OwnerFamily:   OwnerFamilyAutoMode,
+		NamePattern:   "eks-auto-standard-%s-aarch64-%s*",
+		ReleaseFilter: true,
+	},
+	{
+		Name:          "AUTO_MODE_STANDARD_x86_64",
+		AutoMode:      true,
+		OSFamily:      OSFamilyBottlerocket,
+		Architecture:  ArchitectureX86_64,
+		OwnerFamily:   OwnerFamilyAutoMode,
+		NamePattern:   "eks-auto-standard-%s-x86_64-%s*",
+		ReleaseFilter: true,
+	},
+}
+
+// AmiTypeNames returns the names of the AMI types valid in the given mode, in registry order
+func AmiTypeNames(autoMode bool) []string {
+	var names []string
+	for _, t := range AmiTypes {
+		if t.AutoMode == autoMode {
+			names = append(names, t.Name)
+		}
+	}
+	return names
+}
+
+// LookupAmiType returns the registry entry of the AMI type valid in the given mode
+func LookupAmiType(name string, autoMode bool) (AmiType, bool) {
+	for _, t := range AmiTypes {
+		if t.Name == name && t.AutoMode == autoMode {
+			return t, true
+		}
+	}
+	return AmiType{}, false
+}
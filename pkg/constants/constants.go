@@ -5,6 +5,25 @@ const (
 	USAGE string = "Helper tool to find Amazon EKS optimized AMI IDs"
 )
 
+const (
+	OutputFormatTable string = "table"
+	OutputFormatJSON  string = "json"
+	OutputFormatYAML  string = "yaml"
+	OutputFormatCSV   string = "csv"
+
+	// Karpenter EC2NodeClass fragments, search results only
+	OutputFormatKarpenter      string = "karpenter"
+	OutputFormatKarpenterAlias string = "karpenter-alias"
+
+	// Terraform/OpenTofu variables, search results only
+	OutputFormatTfvars string = "tfvars"
+	OutputFormatHCL    string = "hcl"
+
+	// eksctl node group fragments and CloudFormation parameter files, search results only
+	OutputFormatEksctl         string = "eksctl"
+	OutputFormatCloudFormation string = "cloudformation"
+)
+
 var (
 	GitVersion string
 	GoVersion  string
@@ -12,44 +31,18 @@ var (
 )
 
 var (
-	// Valid AMI_TYPE definitions
-	// - https://docs.aws.amazon.com/eks/latest/APIReference/API_Nodegroup.html#AmazonEKS-Type-Nodegroup-amiType
-	ValidAmiTypes = map[string][]string{
-		// General AMI Types for Amazon EKS
-		"DEFAULT": {
-			"AL2_ARM_64",
-			"AL2_x86_64",
-			"AL2_x86_64_GPU",
-			"AL2023_ARM_64_NVIDIA",
-			"AL2023_ARM_64_STANDARD",
-			"AL2023_x86_64_NEURON",
-			"AL2023_x86_64_NVIDIA",
-			"AL2023_x86_64_STANDARD",
-			"BOTTLEROCKET_ARM_64",
-			"BOTTLEROCKET_ARM_64_FIPS",
-			"BOTTLEROCKET_ARM_64_NVIDIA",
-			"BOTTLEROCKET_ARM_64_NVIDIA_FIPS",
-			"BOTTLEROCKET_x86_64",
-			"BOTTLEROCKET_x86_64_FIPS",
-			"BOTTLEROCKET_x86_64_NVIDIA",
-			"BOTTLEROCKET_x86_64_NVIDIA_FIPS",
-			"WINDOWS_CORE_2016_x86_64",
-			"WINDOWS_CORE_2019_x86_64",
-			"WINDOWS_CORE_2022_x86_64",
-			"WINDOWS_CORE_2025_x86_64",
-			"WINDOWS_FULL_2016_x86_64",
-			"WINDOWS_FULL_2019_x86_64",
-			"WINDOWS_FULL_2022_x86_64",
-			"WINDOWS_FULL_2025_x86_64",
-		},
-		// Special crafted AMI Types for Auto Mode
-		"AUTO_MODE": {
-			"AUTO_MODE_NEURON_x86_64",
-			"AUTO_MODE_NVIDIA_ARM_64",
-			"AUTO_MODE_NVIDIA_x86_64",
-			"AUTO_MODE_STANDARD_ARM_64",
-			"AUTO_MODE_STANDARD_x86_64",
-		},
+	// Supported output formats for search results
+	ValidOutputFormats = []string{
+		OutputFormatTable,
+		OutputFormatJSON,
+		OutputFormatYAML,
+		OutputFormatCSV,
+		OutputFormatKarpenter,
+		OutputFormatKarpenterAlias,
+		OutputFormatTfvars,
+		OutputFormatHCL,
+		OutputFormatEksctl,
+		OutputFormatCloudFormation,
 	}
 
 	// Ideally, official AMI should comes from fixed AWS Account IDs, so hard-coded here should be fine.
@@ -0,0 +1,28 @@
+package constants
+
+// KubernetesRelease is the lifecycle of an Amazon EKS Kubernetes version, dates are yyyy-mm-dd in UTC
+type KubernetesRelease struct {
+	Version              string
+	ReleaseDate          string
+	EndOfStandardSupport string
+	EndOfExtendedSupport string
+}
+
+// KubernetesReleases is the lifecycle table of Amazon EKS versions, oldest first.
+// Extended support was introduced with Amazon EKS 1.23, older versions are past their end of life.
+// - https://docs.aws.amazon.com/eks/latest/userguide/kubernetes-versions.html
+var KubernetesReleases = []KubernetesRelease{
+	{Version: "1.23", ReleaseDate: "2022-08-11", EndOfStandardSupport: "2023-10-11", EndOfExtendedSupport: "2024-10-11"},
+	{Version: "1.24", ReleaseDate: "2022-11-15", EndOfStandardSupport: "2024-01-31", EndOfExtendedSupport: "2025-01-31"},
+	{Version: "1.25", ReleaseDate: "2023-02-22", EndOfStandardSupport: "2024-05-01", EndOfExtendedSupport: "2025-05-01"},
+	{Version: "1.26", ReleaseDate: "2023-04-11", EndOfStandardSupport: "2024-06-11", EndOfExtendedSupport: "2025-06-11"},
+	{Version: "1.27", ReleaseDate: "2023-05-24", EndOfStandardSupport: "2024-07-24", EndOfExtendedSupport: "2025-07-24"},
+	{Version: "1.28", ReleaseDate: "2023-09-26", EndOfStandardSupport: "2024-11-26", EndOfExtendedSupport: "2025-11-26"},
+	{Version: "1.29", ReleaseDate: "2024-01-23", EndOfStandardSupport: "2025-03-23", EndOfExtendedSupport: "2026-03-23"},
+	{Version: "1.30", ReleaseDate: "2024-05-23", EndOfStandardSupport: "2025-07-23", EndOfExtendedSupport: "2026-07-23"},
+	{Version: "1.31", ReleaseDate: "2024-09-26", EndOfStandardSupport: "2025-11-26", EndOfExtendedSupport: "2026-11-26"},
+	{Version: "1.32", ReleaseDate: "2025-01-23", EndOfStandardSupport: "2026-03-23", EndOfExtendedSupport: "2027-03-23"},
+	{Version: "1.33", ReleaseDate: "2025-05-29", EndOfStandardSupport: "2026-07-29", EndOfExtendedSupport: "2027-07-29"},
+	{Version: "1.34", ReleaseDate: "2025-10-02", EndOfStandardSupport: "2026-12-02", EndOfExtendedSupport: "2027-12-02"},
+	{Version: "1.35", ReleaseDate: "2026-01-27", EndOfStandardSupport: "2027-03-27", EndOfExtendedSupport: "2028-03-27"},
+}
@@ -0,0 +1,58 @@
+package finder
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+)
+
+// ValidAmiTypes returns the AMI types which could be looked up in the given mode
+func ValidAmiTypes(autoMode bool) []string {
+	return constants.AmiTypeNames(autoMode)
+}
+
+// ExpandAmiTypes expands a comma-separated list of AMI types and glob-style selectors
+// (e.g. AL2023_*, BOTTLEROCKET_*_NVIDIA*) against the valid AMI types, keeping registry order.
+func ExpandAmiTypes(spec string, autoMode bool) ([]string, error) {
+	valid := ValidAmiTypes(autoMode)
+
+	var selected []string
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		matched := false
+		for _, amiType := range valid {
+			if ok, err := path.Match(item, amiType); err != nil {
+				return nil, newQueryError(ErrInvalidAmiType, "invalid ami-type selector '%s': %v", item, err)
+			} else if ok {
+				matched = true
+				if !slices.Contains(selected, amiType) {
+					selected = append(selected, amiType)
+				}
+			}
+		}
+
+		if !matched {
+			if autoMode {
+				return nil, newQueryError(ErrInvalidAmiType, "invalid ami-type '%s' for auto-mode. Valid types: %s", item, strings.Join(valid, ", "))
+			}
+			return nil, newQueryError(ErrInvalidAmiType, "invalid ami-type '%s'. Supported ami-type could be found at https://docs.aws.amazon.com/eks/latest/APIReference/API_Nodegroup.html", item)
+		}
+	}
+
+	if len(selected) == 0 {
+		return []string{DefaultAmiType(autoMode)}, nil
+	}
+
+	// Keep registry order regardless of input order
+	slices.SortFunc(selected, func(a, b string) int {
+		return slices.Index(valid, a) - slices.Index(valid, b)
+	})
+
+	return selected, nil
+}
@@ -0,0 +1,69 @@
+package finder
+
+import (
+	"testing"
+)
+
+func TestAMIFamiliesCoverRegistry(t *testing.T) {
+	// eksctl and Karpenter amiFamily of each AMI type, empty when there is none
+	tests := map[string]struct {
+		eksctl    string
+		karpenter string
+		alias     bool
+	}{
+		"AL2_ARM_64":                      {"AmazonLinux2", KarpenterFamilyAL2, true},
+		"AL2_x86_64":                      {"AmazonLinux2", KarpenterFamilyAL2, true},
+		"AL2_x86_64_GPU":                  {"AmazonLinux2", KarpenterFamilyAL2, true},
+		"AL2023_ARM_64_NVIDIA":            {"AmazonLinux2023", KarpenterFamilyAL2023, true},
+		"AL2023_ARM_64_STANDARD":          {"AmazonLinux2023", KarpenterFamilyAL2023, true},
+		"AL2023_x86_64_NEURON":            {"AmazonLinux2023", KarpenterFamilyAL2023, true},
+		"AL2023_x86_64_NVIDIA":            {"AmazonLinux2023", KarpenterFamilyAL2023, true},
+		"AL2023_x86_64_STANDARD":          {"AmazonLinux2023", KarpenterFamilyAL2023, true},
+		"BOTTLEROCKET_ARM_64":             {"Bottlerocket", KarpenterFamilyBottlerocket, true},
+		"BOTTLEROCKET_ARM_64_FIPS":        {"Bottlerocket", KarpenterFamilyBottlerocket, false},
+		"BOTTLEROCKET_ARM_64_NVIDIA":      {"Bottlerocket", KarpenterFamilyBottlerocket, true},
+		"BOTTLEROCKET_ARM_64_NVIDIA_FIPS": {"Bottlerocket", KarpenterFamilyBottlerocket, false},
+		"BOTTLEROCKET_x86_64":             {"Bottlerocket", KarpenterFamilyBottlerocket, true},
+		"BOTTLEROCKET_x86_64_FIPS":        {"Bottlerocket", KarpenterFamilyBottlerocket, false},
+		"BOTTLEROCKET_x86_64_NVIDIA":      {"Bottlerocket", KarpenterFamilyBottlerocket, true},
+		"BOTTLEROCKET_x86_64_NVIDIA_FIPS": {"Bottlerocket", KarpenterFamilyBottlerocket, false},
+		"WINDOWS_CORE_2016_x86_64":        {},
+		"WINDOWS_CORE_2019_x86_64":        {"WindowsServer2019CoreContainer", KarpenterFamilyWindows2019, true},
+		"WINDOWS_CORE_2022_x86_64":        {"WindowsServer2022CoreContainer", KarpenterFamilyWindows2022, true},
+		"WINDOWS_CORE_2025_x86_64":        {},
+		"WINDOWS_FULL_2016_x86_64":        {},
+		"WINDOWS_FULL_2019_x86_64":        {"WindowsServer2019FullContainer", KarpenterFamilyWindows2019, false},
+		"WINDOWS_FULL_2022_x86_64":        {"WindowsServer2022FullContainer", KarpenterFamilyWindows2022, false},
+		"WINDOWS_FULL_2025_x86_64":        {},
+	}
+
+	for _, amiType := range ValidAmiTypes(false) {
+		tt, ok := tests[amiType]
+		if !ok {
+			t.Errorf("%s is missing from the test table", amiType)
+			continue
+		}
+
+		eksctl, err := EksctlAMIFamily(amiType)
+		if eksctl != tt.eksctl || (err == nil) != (tt.eksctl != "") {
+			t.Errorf("EksctlAMIFamily(%s) = %q, %v, want %q", amiType, eksctl, err, tt.eksctl)
+		}
+		karpenter, err := KarpenterAMIFamily(amiType)
+		if karpenter != tt.karpenter || (err == nil) != (tt.karpenter != "") {
+			t.Errorf("KarpenterAMIFamily(%s) = %q, %v, want %q", amiType, karpenter, err, tt.karpenter)
+		}
+		if got := aliasable(amiType); got != tt.alias {
+			t.Errorf("aliasable(%s) = %v, want %v", amiType, got, tt.alias)
+		}
+	}
+
+	// Auto Mode AMIs are managed by Amazon EKS, neither eksctl nor Karpenter select them
+	for _, amiType := range ValidAmiTypes(true) {
+		if _, err := EksctlAMIFamily(amiType); err == nil {
+			t.Errorf("EksctlAMIFamily(%s) error = nil, want error", amiType)
+		}
+		if _, err := KarpenterAMIFamily(amiType); err == nil {
+			t.Errorf("KarpenterAMIFamily(%s) error = nil, want error", amiType)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package finder
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Bottlerocket AMI names end with the OS release and the short commit hash of the build,
+// e.g. bottlerocket-aws-k8s-1.35-x86_64-v1.52.0-c9c6f9ff
+var bottlerocketNameRe = regexp.MustCompile(`^bottlerocket-aws-k8s-.+-v(\d+\.\d+\.\d+)-([0-9a-f]+)$`)
+
+// Semver is a MAJOR.MINOR.PATCH version as used by Bottlerocket releases
+type Semver struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+func (v Semver) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns a negative number, zero or a positive number when v is lower, equal or greater than o
+func (v Semver) Compare(o Semver) int {
+	if v.Major != o.Major {
+		return v.Major - o.Major
+	}
+	if v.Minor != o.Minor {
+		return v.Minor - o.Minor
+	}
+	return v.Patch - o.Patch
+}
+
+// ParseSemver parses X.Y.Z with an optional leading "v"
+func ParseSemver(v string) (Semver, error) {
+	parts := strings.Split(strings.TrimPrefix(v, "v"), ".")
+	if len(parts) != 3 {
+		return Semver{}, fmt.Errorf("invalid version '%s'. Expected format: X.Y.Z (e.g., 1.51.0)", v)
+	}
+
+	var nums [3]int
+	for idx, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Semver{}, fmt.Errorf("invalid version '%s'. Expected format: X.Y.Z (e.g., 1.51.0)", v)
+		}
+		nums[idx] = n
+	}
+
+	return Semver{Major: nums[0], Minor: nums[1], Patch: nums[2]}, nil
+}
+
+// ParseBottlerocketName extracts the Bottlerocket OS release and build hash from an AMI name
+func ParseBottlerocketName(name string) (version Semver, buildHash string, ok bool) {
+	m := bottlerocketNameRe.FindStringSubmatch(name)
+	if m == nil {
+		return Semver{}, "", false
+	}
+
+	version, err := ParseSemver(m[1])
+	if err != nil {
+		return Semver{}, "", false
+	}
+
+	return version, m[2], true
+}
+
+type versionComparison struct {
+	op      string
+	version Semver
+}
+
+// VersionConstraint is a set of comparisons which must all hold, e.g. ">=1.50.0 <1.52.0"
+type VersionConstraint []versionComparison
+
+// Supported operators, longest first so that ">=" is not taken for ">"
+var constraintOperators = []string{">=", "<=", "!=", "==", ">", "<", "="}
+
+// ParseVersionConstraint parses space or comma separated comparisons, a bare version is an exact match
+func ParseVersionConstraint(spec string) (VersionConstraint, error) {
+	var c VersionConstraint
+	for _, term := range strings.FieldsFunc(spec, func(r rune) bool { return r == ' ' || r == ',' }) {
+		op := "="
+		for _, candidate := range constraintOperators {
+			if strings.HasPrefix(term, candidate) {
+				op, term = candidate, strings.TrimPrefix(term, candidate)
+				break
+			}
+		}
+		if op == "==" {
+			op = "="
+		}
+
+		version, err := ParseSemver(term)
+		if err != nil {
+			return nil, err
+		}
+		c = append(c, versionComparison{op: op, version: version})
+	}
+
+	if len(c) == 0 {
+		return nil, fmt.Errorf("version constraint must not be empty")
+	}
+
+	return c, nil
+}
+
+// Match reports whether v satisfies every comparison of the constraint
+func (c VersionConstraint) Match(v Semver) bool {
+	for _, cmp := range c {
+		r := v.Compare(cmp.version)
+		var ok bool
+		switch cmp.op {
+		case "=":
+			ok = r == 0
+		case "!=":
+			ok = r != 0
+		case ">":
+			ok = r > 0
+		case ">=":
+			ok = r >= 0
+		case "<":
+			ok = r < 0
+		case "<=":
+			ok = r <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Exact returns the version when the constraint only matches a single version
+func (c VersionConstraint) Exact() (Semver, bool) {
+	if len(c) == 1 && c[0].op == "=" {
+		return c[0].version, true
+	}
+	return Semver{}, false
+}
+
+func (c VersionConstraint) String() string {
+	terms := make([]string, 0, len(c))
+	for _, cmp := range c {
+		terms = append(terms, cmp.op+cmp.version.String())
+	}
+	return strings.Join(terms, " ")
+}
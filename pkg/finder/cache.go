@@ -0,0 +1,58 @@
+package finder
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// CacheKey identifies a DescribeImages lookup, the images returned only depend on these
+type CacheKey struct {
+	Region            string `json:"region"`
+	OwnerId           string `json:"ownerId"`
+	NamePattern       string `json:"namePattern"`
+	IncludeDeprecated bool   `json:"includeDeprecated"`
+}
+
+// ImageCache keeps every image matching a lookup between runs, see package cache.
+// Get reports whether usable images were found and when they were stored.
+type ImageCache interface {
+	Get(key CacheKey) (images []types.Image, storedAt time.Time, ok bool)
+	Put(key CacheKey, images []types.Image) error
+}
+
+// findImages returns the top matching images, through the cache when one is set.
+// Every image matching the filters is cached since queries sharing a key could still filter differently.
+func (f *Finder) findImages(ctx context.Context, key CacheKey, input *ec2.DescribeImagesInput, maxResults int, m imageMatcher) ([]types.Image, time.Time, error) {
+	if f.cache == nil {
+		images, err := findAmiMatches(ctx, f.client, input, maxResults, m)
+		if err != nil {
+			return nil, time.Time{}, wrapAPIError(ctx, err)
+		}
+		return images, time.Time{}, nil
+	}
+
+	all, storedAt, ok := f.cache.Get(key)
+	if !ok {
+		if f.client == nil {
+			return nil, time.Time{}, newQueryError(ErrCacheMiss, "no cached result for %s in %s region, search once while online to fill the cache", key.NamePattern, key.Region)
+		}
+
+		all = []types.Image{}
+		if err := describeImages(ctx, f.client, input, func(i types.Image) { all = append(all, i) }); err != nil {
+			return nil, time.Time{}, wrapAPIError(ctx, err)
+		}
+
+		// A broken cache must not fail the lookup, the next run simply calls DescribeImages again
+		_ = f.cache.Put(key, all)
+		storedAt = time.Time{}
+	}
+
+	top := newTopImages(maxResults, m)
+	for _, i := range all {
+		top.add(i)
+	}
+	return top.images(), storedAt, nil
+}
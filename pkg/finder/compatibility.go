@@ -0,0 +1,181 @@
+package finder
+
+import (
+	"path"
+	"slices"
+)
+
+// CompatibilityRule bounds the Amazon EKS versions the AMI types matching a glob-style selector are published for
+type CompatibilityRule struct {
+	AmiTypes   string // glob-style selector, e.g. AL2023_* or BOTTLEROCKET_*_NVIDIA_FIPS
+	AutoMode   bool
+	MinVersion string // inclusive, empty means unbounded
+	MaxVersion string // inclusive, empty means unbounded
+	Sources    []string
+}
+
+// CompatibilityRules is the compatibility matrix, every rule matching an AMI type applies
+var CompatibilityRules = []CompatibilityRule{
+	{
+		// Auto Mode only available for Amazon EKS 1.29 or later
+		AmiTypes:   "AUTO_MODE_*",
+		AutoMode:   true,
+		MinVersion: "1.29",
+		Sources: []string{
+			"https://docs.aws.amazon.com/eks/latest/userguide/create-auto.html",
+		},
+	},
+	{
+		// AL2 AMI will no longer be supported for Amazon EKS 1.33 or newer
+		AmiTypes:   "AL2_*",
+		MaxVersion: "1.32",
+		Sources: []string{
+			"https://docs.aws.amazon.com/eks/latest/userguide/eks-ami-deprecation-faqs.html",
+		},
+	},
+	{
+		// AL2023 AMI support starting from Amazon EKS 1.23 or newer
+		AmiTypes:   "AL2023_*",
+		MinVersion: "1.23",
+		Sources: []string{
+			"https://aws.amazon.com/blogs/containers/amazon-eks-optimized-amazon-linux-2023-amis-now-available/",
+			"https://aws.amazon.com/blogs/containers/amazon-eks-optimized-amazon-linux-2023-accelerated-amis-now-available/",
+			"https://docs.aws.amazon.com/eks/latest/userguide/doc-history.html",
+		},
+	},
+	{
+		// Bottlerocket AMI initially support Amazon EKS 1.15 or newer
+		AmiTypes:   "BOTTLEROCKET_*",
+		MinVersion: "1.15",
+		Sources: []string{
+			"https://aws.amazon.com/blogs/containers/amazon-eks-adds-native-support-for-bottlerocket-in-managed-node-groups/",
+			"https://github.com/bottlerocket-os/bottlerocket/releases/tag/v1.0.0",
+			"https://docs.aws.amazon.com/eks/latest/userguide/doc-history.html",
+		},
+	},
+	{
+		// Bottlerocket NVIDIA FIPS variants only available for Kubernetes 1.29+
+		AmiTypes:   "BOTTLEROCKET_*_NVIDIA_FIPS",
+		MinVersion: "1.29",
+		Sources: []string{
+			"https://github.com/bottlerocket-os/bottlerocket/releases/tag/v1.51.0",
+			"https://github.com/bottlerocket-os/bottlerocket/pull/4671",
+		},
+	},
+	{
+		// Windows Server AMI initially support Amazon EKS 1.14 or newer
+		AmiTypes:   "WINDOWS_*",
+		MinVersion: "1.14",
+		Sources: []string{
+			"https://docs.aws.amazon.com/eks/latest/userguide/doc-history.html",
+			"https://github.com/aws/containers-roadmap/issues/69#issuecomment-539641916",
+		},
+	},
+	{
+		// Windows Server 2019 only support Amazon EKS 1.23 or newer
+		AmiTypes:   "WINDOWS_*_2019_*",
+		MinVersion: "1.23",
+		Sources: []string{
+			"https://aws.amazon.com/blogs/containers/deploying-amazon-eks-windows-managed-node-groups/",
+			"https://docs.aws.amazon.com/eks/latest/userguide/doc-history.html",
+		},
+	},
+	{
+		// Windows Server 2022 only support Amazon EKS 1.23 or newer
+		AmiTypes:   "WINDOWS_*_2022_*",
+		MinVersion: "1.23",
+		Sources: []string{
+			"https://aws.amazon.com/blogs/containers/deploying-amazon-eks-windows-managed-node-groups/",
+			"https://docs.aws.amazon.com/eks/latest/userguide/doc-history.html",
+		},
+	},
+	{
+		// Windows Server 2025 only support Amazon EKS 1.35 or newer
+		AmiTypes:   "WINDOWS_*_2025_*",
+		MinVersion: "1.35",
+		Sources: []string{
+			"https://docs.aws.amazon.com/eks/latest/userguide/doc-history.html",
+		},
+	},
+}
+
+// Compatibility is the effective range of Amazon EKS versions of an AMI type, every matching rule combined
+type Compatibility struct {
+	AmiType    string
+	AutoMode   bool
+	MinVersion string // inclusive, empty means unbounded
+	MaxVersion string // inclusive, empty means unbounded
+	Sources    []string
+
+	minSource, maxSource string // first source of the rules setting each bound
+}
+
+// CompatibilityOf combines the rules matching the AMI type, the tightest bounds win
+func CompatibilityOf(amiType string, autoMode bool) Compatibility {
+	c := Compatibility{AmiType: amiType, AutoMode: autoMode}
+	for _, r := range CompatibilityRules {
+		if r.AutoMode != autoMode {
+			continue
+		}
+		if ok, _ := path.Match(r.AmiTypes, amiType); !ok {
+			continue
+		}
+
+		if r.MinVersion != "" && (c.MinVersion == "" || CompareKubernetesVersions(r.MinVersion, c.MinVersion) > 0) {
+			c.MinVersion = r.MinVersion
+			c.minSource = firstSource(r)
+		}
+		if r.MaxVersion != "" && (c.MaxVersion == "" || CompareKubernetesVersions(r.MaxVersion, c.MaxVersion) < 0) {
+			c.MaxVersion = r.MaxVersion
+			c.maxSource = firstSource(r)
+		}
+		for _, source := range r.Sources {
+			if !slices.Contains(c.Sources, source) {
+				c.Sources = append(c.Sources, source)
+			}
+		}
+	}
+	return c
+}
+
+func firstSource(r CompatibilityRule) string {
+	if len(r.Sources) == 0 {
+		return ""
+	}
+	return r.Sources[0]
+}
+
+// Supports reports whether the AMI type is published for the Kubernetes version
+func (c Compatibility) Supports(kubernetesVersion string) bool {
+	if c.MinVersion != "" && CompareKubernetesVersions(kubernetesVersion, c.MinVersion) < 0 {
+		return false
+	}
+	if c.MaxVersion != "" && CompareKubernetesVersions(kubernetesVersion, c.MaxVersion) > 0 {
+		return false
+	}
+	return true
+}
+
+// check returns an ErrUnsupportedVersion QueryError when the AMI type isn't published for the Kubernetes version
+func (c Compatibility) check(kubernetesVersion string) error {
+	if c.Supports(kubernetesVersion) {
+		return nil
+	}
+
+	name := c.AmiType
+	if c.AutoMode {
+		name = "EKS Auto Mode"
+	}
+
+	see := func(source string) string {
+		if source == "" {
+			return ""
+		}
+		return ". See: " + source
+	}
+
+	if c.MinVersion != "" && CompareKubernetesVersions(kubernetesVersion, c.MinVersion) < 0 {
+		return newQueryError(ErrUnsupportedVersion, "%s requires Amazon EKS %s or newer (you specified %s)%s", name, c.MinVersion, kubernetesVersion, see(c.minSource))
+	}
+	return newQueryError(ErrUnsupportedVersion, "%s is not supported for Amazon EKS newer than %s (you specified %s)%s", name, c.MaxVersion, kubernetesVersion, see(c.maxSource))
+}
@@ -0,0 +1,161 @@
+package finder
+
+import (
+	"errors"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+)
+
+func TestCompatibilityRuleBoundaries(t *testing.T) {
+	tests := []struct {
+		amiType   string
+		autoMode  bool
+		version   string
+		supported bool
+	}{
+		// AUTO_MODE_*: 1.29 or newer
+		{"AUTO_MODE_STANDARD_x86_64", true, "1.28", false},
+		{"AUTO_MODE_STANDARD_x86_64", true, "1.29", true},
+		{"AUTO_MODE_NVIDIA_ARM_64", true, "1.28", false},
+		{"AUTO_MODE_NVIDIA_ARM_64", true, "1.29", true},
+
+		// AL2_*: 1.32 or older
+		{"AL2_x86_64", false, "1.32", true},
+		{"AL2_x86_64", false, "1.33", false},
+		{"AL2_ARM_64", false, "1.32", true},
+		{"AL2_ARM_64", false, "1.33", false},
+		{"AL2_x86_64_GPU", false, "1.32", true},
+		{"AL2_x86_64_GPU", false, "1.33", false},
+
+		// AL2023_*: 1.23 or newer
+		{"AL2023_x86_64_STANDARD", false, "1.22", false},
+		{"AL2023_x86_64_STANDARD", false, "1.23", true},
+		{"AL2023_ARM_64_NVIDIA", false, "1.22", false},
+		{"AL2023_ARM_64_NVIDIA", false, "1.23", true},
+		{"AL2023_x86_64_NEURON", false, "1.22", false},
+		{"AL2023_x86_64_NEURON", false, "1.23", true},
+
+		// BOTTLEROCKET_*: 1.15 or newer
+		{"BOTTLEROCKET_x86_64", false, "1.14", false},
+		{"BOTTLEROCKET_x86_64", false, "1.15", true},
+		{"BOTTLEROCKET_ARM_64_FIPS", false, "1.14", false},
+		{"BOTTLEROCKET_ARM_64_FIPS", false, "1.15", true},
+
+		// BOTTLEROCKET_*_NVIDIA_FIPS: 1.29 or newer, other FIPS and NVIDIA variants are not affected
+		{"BOTTLEROCKET_x86_64_NVIDIA_FIPS", false, "1.28", false},
+		{"BOTTLEROCKET_x86_64_NVIDIA_FIPS", false, "1.29", true},
+		{"BOTTLEROCKET_ARM_64_NVIDIA_FIPS", false, "1.28", false},
+		{"BOTTLEROCKET_ARM_64_NVIDIA_FIPS", false, "1.29", true},
+		{"BOTTLEROCKET_x86_64_FIPS", false, "1.28", true},
+		{"BOTTLEROCKET_x86_64_NVIDIA", false, "1.28", true},
+
+		// WINDOWS_*: 1.14 or newer
+		{"WINDOWS_CORE_2016_x86_64", false, "1.13", false},
+		{"WINDOWS_CORE_2016_x86_64", false, "1.14", true},
+		{"WINDOWS_FULL_2016_x86_64", false, "1.13", false},
+		{"WINDOWS_FULL_2016_x86_64", false, "1.14", true},
+
+		// WINDOWS_*_2019_*: 1.23 or newer
+		{"WINDOWS_CORE_2019_x86_64", false, "1.22", false},
+		{"WINDOWS_CORE_2019_x86_64", false, "1.23", true},
+		{"WINDOWS_FULL_2019_x86_64", false, "1.22", false},
+		{"WINDOWS_FULL_2019_x86_64", false, "1.23", true},
+
+		// WINDOWS_*_2022_*: 1.23 or newer
+		{"WINDOWS_CORE_2022_x86_64", false, "1.22", false},
+		{"WINDOWS_CORE_2022_x86_64", false, "1.23", true},
+		{"WINDOWS_FULL_2022_x86_64", false, "1.22", false},
+		{"WINDOWS_FULL_2022_x86_64", false, "1.23", true},
+
+		// WINDOWS_*_2025_*: 1.35 or newer
+		{"WINDOWS_CORE_2025_x86_64", false, "1.34", false},
+		{"WINDOWS_CORE_2025_x86_64", false, "1.35", true},
+		{"WINDOWS_FULL_2025_x86_64", false, "1.34", false},
+		{"WINDOWS_FULL_2025_x86_64", false, "1.35", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.amiType+"/"+tt.version, func(t *testing.T) {
+			if got := CompatibilityOf(tt.amiType, tt.autoMode).Supports(tt.version); got != tt.supported {
+				t.Errorf("Supports(%s) = %t, want %t", tt.version, got, tt.supported)
+			}
+
+			err := Query{
+				Region:            "us-east-1",
+				AmiType:           tt.amiType,
+				AutoMode:          tt.autoMode,
+				KubernetesVersion: tt.version,
+			}.Validate()
+			switch {
+			case tt.supported && err != nil:
+				t.Errorf("Validate() error = %v, want nil", err)
+			case !tt.supported && !errors.Is(err, ErrUnsupportedVersion):
+				t.Errorf("Validate() error = %v, want ErrUnsupportedVersion", err)
+			}
+		})
+	}
+}
+
+func TestCompatibilityOfTightestBoundsWin(t *testing.T) {
+	tests := []struct {
+		amiType    string
+		autoMode   bool
+		minVersion string
+		maxVersion string
+		source     string // expected in the error of a version below the minimum, or above the maximum
+	}{
+		{"AL2_x86_64", false, "", "1.32", "eks-ami-deprecation-faqs"},
+		{"AL2023_x86_64_STANDARD", false, "1.23", "", "amazon-linux-2023-amis-now-available"},
+		{"BOTTLEROCKET_x86_64", false, "1.15", "", "native-support-for-bottlerocket"},
+		{"BOTTLEROCKET_x86_64_NVIDIA_FIPS", false, "1.29", "", "bottlerocket/releases/tag/v1.51.0"},
+		{"WINDOWS_CORE_2016_x86_64", false, "1.14", "", "doc-history"},
+		{"WINDOWS_CORE_2025_x86_64", false, "1.35", "", "doc-history"},
+		{"AUTO_MODE_STANDARD_x86_64", true, "1.29", "", "create-auto"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.amiType, func(t *testing.T) {
+			c := CompatibilityOf(tt.amiType, tt.autoMode)
+			if c.MinVersion != tt.minVersion || c.MaxVersion != tt.maxVersion {
+				t.Errorf("CompatibilityOf() = [%s, %s], want [%s, %s]", c.MinVersion, c.MaxVersion, tt.minVersion, tt.maxVersion)
+			}
+
+			version := "1.10"
+			if tt.maxVersion != "" {
+				version = "1.99"
+			}
+			if err := c.check(version); err == nil || !strings.Contains(err.Error(), tt.source) {
+				t.Errorf("check(%s) error = %v, want a link containing %q", version, err, tt.source)
+			}
+		})
+	}
+}
+
+func TestCompatibilityRulesMatchRegistry(t *testing.T) {
+	for _, r := range CompatibilityRules {
+		if _, err := path.Match(r.AmiTypes, ""); err != nil {
+			t.Errorf("rule %s: invalid selector: %v", r.AmiTypes, err)
+			continue
+		}
+
+		matched := false
+		for _, amiType := range constants.AmiTypes {
+			if ok, _ := path.Match(r.AmiTypes, amiType.Name); ok && amiType.AutoMode == r.AutoMode {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			t.Errorf("rule %s matches no AMI type of the registry", r.AmiTypes)
+		}
+		if r.MinVersion == "" && r.MaxVersion == "" {
+			t.Errorf("rule %s sets neither a minimum nor a maximum version", r.AmiTypes)
+		}
+		if len(r.Sources) == 0 {
+			t.Errorf("rule %s has no source", r.AmiTypes)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package finder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layouts accepted for absolute --since/--until input, date-only layouts come first
+var absoluteTimeLayouts = []string{
+	"2006-01-02",
+	"20060102",
+	time.RFC3339,
+}
+
+// parseTimeSpec parses an absolute date/time or a relative age like 12h, 30d or 4w (counting back from now),
+// dateOnly reports whether v names a whole day rather than an instant.
+func parseTimeSpec(v string, now time.Time) (t time.Time, dateOnly bool, err error) {
+	v = strings.TrimSpace(v)
+	for idx, layout := range absoluteTimeLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t.UTC(), idx < 2, nil
+		}
+	}
+
+	if len(v) >= 2 {
+		if n, err := strconv.Atoi(v[:len(v)-1]); err == nil && n >= 0 {
+			switch v[len(v)-1] {
+			case 'h':
+				return now.Add(-time.Duration(n) * time.Hour).UTC(), false, nil
+			case 'd':
+				return now.AddDate(0, 0, -n).UTC(), false, nil
+			case 'w':
+				return now.AddDate(0, 0, -7*n).UTC(), false, nil
+			}
+		}
+	}
+
+	return time.Time{}, false, fmt.Errorf("invalid time '%s'. Expected [yyyy-mm-dd], [yyyymmdd], RFC 3339 or a relative age like 12h, 30d or 4w", v)
+}
+
+// ParseSince returns the inclusive lower bound of CreationDate described by v
+func ParseSince(v string, now time.Time) (time.Time, error) {
+	t, _, err := parseTimeSpec(v, now)
+	return t, err
+}
+
+// ParseUntil returns the exclusive upper bound of CreationDate described by v,
+// a date-only input covers the whole day.
+func ParseUntil(v string, now time.Time) (time.Time, error) {
+	t, dateOnly, err := parseTimeSpec(v, now)
+	if err == nil && dateOnly {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, err
+}
+
+// inCreationRange reports whether creationDate falls into [since, until), zero bounds are open
+func inCreationRange(creationDate, since, until time.Time) bool {
+	if !since.IsZero() && creationDate.Before(since) {
+		return false
+	}
+	if !until.IsZero() && !creationDate.Before(until) {
+		return false
+	}
+	return true
+}
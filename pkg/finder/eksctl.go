@@ -0,0 +1,15 @@
+package finder
+
+import (
+	"fmt"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+)
+
+// EksctlAMIFamily maps an AMI type to the amiFamily of an eksctl node group, see constants.AmiType
+func EksctlAMIFamily(amiType string) (string, error) {
+	if t, ok := constants.LookupAmiType(amiType, false); ok && t.EksctlFamily != "" {
+		return t.EksctlFamily, nil
+	}
+	return "", fmt.Errorf("%s has no matching eksctl amiFamily", amiType)
+}
@@ -0,0 +1,64 @@
+package finder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+)
+
+var (
+	ErrInvalidRegion              = errors.New("invalid region")
+	ErrInvalidAmiType             = errors.New("invalid ami type")
+	ErrUnsupportedVersion         = errors.New("unsupported kubernetes version")
+	ErrInvalidReleaseDate         = errors.New("invalid release date")
+	ErrInvalidDateRange           = errors.New("invalid date range")
+	ErrInvalidBottlerocketVersion = errors.New("invalid bottlerocket version")
+	ErrOwnerNotFound              = errors.New("owner not found")
+	ErrNotFound                   = errors.New("not found")
+	ErrCacheMiss                  = errors.New("cache miss")
+)
+
+// QueryError describes why a Query was rejected, use errors.Is with the Err* values above to tell them apart
+type QueryError struct {
+	Kind error
+	Msg  string
+}
+
+func (e *QueryError) Error() string { return e.Msg }
+func (e *QueryError) Unwrap() error { return e.Kind }
+
+func newQueryError(kind error, format string, args ...any) error {
+	return &QueryError{Kind: kind, Msg: fmt.Sprintf(format, args...)}
+}
+
+// APIError wraps failures returned by the EC2 API
+type APIError struct {
+	RequestID string
+	Err       error
+}
+
+func (e *APIError) Error() string {
+	if e.RequestID == "" {
+		return fmt.Sprintf("error retrieving AMI information: %v", e.Err)
+	}
+	return fmt.Sprintf("AWS error (requestID: %s): %v", e.RequestID, e.Err)
+}
+func (e *APIError) Unwrap() error { return e.Err }
+
+// wrapAPIError turns errors returned by the EC2 client into cancellation or APIError
+func wrapAPIError(ctx context.Context, err error) error {
+	// Check for context cancellation first
+	if ctx.Err() != nil {
+		return fmt.Errorf("request cancelled or timed out: %w", ctx.Err())
+	}
+
+	// Check for AWS-specific errors
+	var re *awshttp.ResponseError
+	if errors.As(err, &re) {
+		return &APIError{RequestID: re.ServiceRequestID(), Err: re.Unwrap()}
+	}
+
+	return &APIError{Err: err}
+}
@@ -0,0 +1,308 @@
+// Package finder looks up Amazon EKS optimized AMIs published by the official owner accounts.
+package finder
+
+import (
+	"container/heap"
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+)
+
+// Timestamp layout used by EC2 for CreationDate and DeprecationTime
+const TimeLayout = "2006-01-02T15:04:05.000Z"
+
+// Image is an AMI returned by DescribeImages with its timestamps parsed
+type Image struct {
+	ImageId         string
+	Name            string
+	Description     string
+	OwnerId         string
+	Architecture    string
+	CreationDate    time.Time
+	DeprecationTime time.Time // zero when no deprecation time is set
+	Recommended     bool      // currently recommended by AWS through SSM public parameters
+
+	BottlerocketVersion string // OS release parsed from the name, Bottlerocket only
+	BuildHash           string // build commit parsed from the name, Bottlerocket only
+
+	Metadata Metadata // parsed from name and description, see ParseMetadata
+}
+
+// Result holds the newest matching images, sorted by creation date in descending order
+// (Bottlerocket images by OS release first)
+type Result struct {
+	Region      string
+	OwnerId     string
+	OwnerSource string // where OwnerId comes from, the query, built-in mappings or an override file
+	NamePattern string
+	Parameter   string    // SSM parameter the image is resolved from, recommended lookup only
+	CachedAt    time.Time // when the images were stored, zero unless served from the cache
+	Images      []Image
+}
+
+// Finder searches AMIs through the given EC2 client, the client decides which region is queried
+type Finder struct {
+	client ec2.DescribeImagesAPIClient
+	cache  ImageCache
+}
+
+// New returns a Finder, a nil client serves Find from the cache only, see WithCache
+func New(client ec2.DescribeImagesAPIClient) *Finder {
+	return &Finder{client: client}
+}
+
+// WithCache makes Find look up images in the cache before calling DescribeImages
+func (f *Finder) WithCache(cache ImageCache) *Finder {
+	f.cache = cache
+	return f
+}
+
+// Find validates the query, resolves owner and name pattern, then looks up the newest matching images
+func (f *Finder) Find(ctx context.Context, q Query) (*Result, error) {
+	if q.AmiType == "" {
+		q.AmiType = DefaultAmiType(q.AutoMode)
+	}
+	if q.MaxResults <= 0 {
+		q.MaxResults = DefaultMaxResults
+	}
+
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	ownerId, ownerSource, err := ResolveOwner(q)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern, err := NamePattern(q)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := []types.Filter{
+		{
+			Name: aws.String("owner-id"),
+			Values: []string{
+				ownerId,
+			},
+		},
+		{
+			Name: aws.String("name"),
+			Values: []string{
+				pattern,
+			},
+		},
+	}
+
+	describeImagesInput := ec2.DescribeImagesInput{
+		Filters:           filters,
+		NextToken:         nil,
+		MaxResults:        aws.Int32(1000), // largest page size allowed, fewer round trips
+		IncludeDeprecated: aws.Bool(q.IncludeDeprecated),
+	}
+
+	key := CacheKey{
+		Region:            q.Region,
+		OwnerId:           ownerId,
+		NamePattern:       pattern,
+		IncludeDeprecated: q.IncludeDeprecated,
+	}
+	images, cachedAt, err := f.findImages(ctx, key, &describeImagesInput, q.MaxResults, newImageMatcher(q))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Region:      q.Region,
+		OwnerId:     ownerId,
+		OwnerSource: ownerSource,
+		NamePattern: pattern,
+		CachedAt:    cachedAt,
+		Images:      make([]Image, 0, len(images)),
+	}
+	for _, i := range images {
+		result.Images = append(result.Images, newImage(i))
+	}
+
+	return result, nil
+}
+
+func newImage(i types.Image) Image {
+	image := Image{
+		ImageId:      aws.ToString(i.ImageId),
+		Name:         aws.ToString(i.Name),
+		Description:  aws.ToString(i.Description),
+		OwnerId:      aws.ToString(i.OwnerId),
+		Architecture: string(i.Architecture),
+	}
+	if t, err := time.Parse(TimeLayout, aws.ToString(i.CreationDate)); err == nil {
+		image.CreationDate = t
+	}
+	if t, err := time.Parse(TimeLayout, aws.ToString(i.DeprecationTime)); err == nil {
+		image.DeprecationTime = t
+	}
+	if v, buildHash, ok := ParseBottlerocketName(image.Name); ok {
+		image.BottlerocketVersion = v.String()
+		image.BuildHash = buildHash
+	}
+	image.Metadata, _ = ParseMetadata(image.Name, image.Description)
+	return image
+}
+
+// imageMatcher decides which images are kept and how they are ordered
+type imageMatcher struct {
+	keep  func(i types.Image) bool    // nil keeps every image
+	older func(a, b types.Image) bool // reports whether a should be ranked after b
+}
+
+// byCreationDate ranks images by CreationDate, ISO 8601 so string comparison is good enough here.
+// Ties are broken by ImageId, so results don't depend on the order pages are returned in.
+func byCreationDate(a, b types.Image) bool {
+	if ca, cb := aws.ToString(a.CreationDate), aws.ToString(b.CreationDate); ca != cb {
+		return ca < cb
+	}
+	return aws.ToString(a.ImageId) > aws.ToString(b.ImageId)
+}
+
+// byBottlerocketVersion ranks images by the OS release parsed from the name, then by CreationDate
+func byBottlerocketVersion(a, b types.Image) bool {
+	va, _, okA := ParseBottlerocketName(aws.ToString(a.Name))
+	vb, _, okB := ParseBottlerocketName(aws.ToString(b.Name))
+	switch {
+	case okA && okB && va.Compare(vb) != 0:
+		return va.Compare(vb) < 0
+	case okA != okB:
+		return !okA // unparsable names rank last
+	}
+	return byCreationDate(a, b)
+}
+
+// newImageMatcher builds the matcher for a validated query
+func newImageMatcher(q Query) imageMatcher {
+	var filters []func(i types.Image) bool
+
+	if !q.Since.IsZero() || !q.Until.IsZero() {
+		filters = append(filters, func(i types.Image) bool {
+			creationDate, err := time.Parse(TimeLayout, aws.ToString(i.CreationDate))
+			return err == nil && inCreationRange(creationDate, q.Since, q.Until)
+		})
+	}
+
+	if c, err := ParseVersionConstraint(q.BottlerocketVersion); err == nil {
+		filters = append(filters, func(i types.Image) bool {
+			v, _, ok := ParseBottlerocketName(aws.ToString(i.Name))
+			return ok && c.Match(v)
+		})
+	}
+
+	m := imageMatcher{older: byCreationDate}
+	if t, ok := constants.LookupAmiType(q.AmiType, q.AutoMode); ok && isBottlerocket(t) {
+		m.older = byBottlerocketVersion
+	}
+	if len(filters) > 0 {
+		m.keep = func(i types.Image) bool {
+			for _, keep := range filters {
+				if !keep(i) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+
+	return m
+}
+
+// imageHeap is a min-heap of images, the lowest ranked image sits on top
+type imageHeap struct {
+	images []types.Image
+	older  func(a, b types.Image) bool
+}
+
+func (h imageHeap) Len() int           { return len(h.images) }
+func (h imageHeap) Less(i, j int) bool { return h.older(h.images[i], h.images[j]) }
+func (h imageHeap) Swap(i, j int)      { h.images[i], h.images[j] = h.images[j], h.images[i] }
+func (h *imageHeap) Push(x any)        { h.images = append(h.images, x.(types.Image)) }
+func (h *imageHeap) Pop() any {
+	old := h.images
+	n := len(old)
+	x := old[n-1]
+	h.images = old[:n-1]
+	return x
+}
+
+// topImages keeps only the top maxResults images accepted by the matcher
+type topImages struct {
+	h          *imageHeap
+	keep       func(i types.Image) bool
+	maxResults int
+}
+
+func newTopImages(maxResults int, m imageMatcher) *topImages {
+	return &topImages{
+		h:          &imageHeap{images: make([]types.Image, 0, maxResults), older: m.older},
+		keep:       m.keep,
+		maxResults: maxResults,
+	}
+}
+
+func (t *topImages) add(image types.Image) {
+	if t.keep != nil && !t.keep(image) {
+		return
+	}
+
+	if t.h.Len() < t.maxResults {
+		heap.Push(t.h, image)
+	} else if t.h.older(t.h.images[0], image) {
+		t.h.images[0] = image
+		heap.Fix(t.h, 0)
+	}
+}
+
+// images drains the heap, top ranked first
+func (t *topImages) images() []types.Image {
+	images := make([]types.Image, t.h.Len())
+	for i := len(images) - 1; i >= 0; i-- {
+		images[i] = heap.Pop(t.h).(types.Image)
+	}
+	return images
+}
+
+// describeImages walks through all pages and calls fn for every image
+func describeImages(ctx context.Context, svc ec2.DescribeImagesAPIClient, input *ec2.DescribeImagesInput, fn func(types.Image)) error {
+	paginator := ec2.NewDescribeImagesPaginator(svc, input)
+	for paginator.HasMorePages() {
+		// Check for context cancellation
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, image := range out.Images {
+			fn(image)
+		}
+	}
+
+	return nil
+}
+
+// findAmiMatches walks through all pages and keeps only the top maxResults images accepted by the matcher,
+// DescribeImages returns images in no particular order so truncating early is not an option.
+func findAmiMatches(ctx context.Context, svc ec2.DescribeImagesAPIClient, input *ec2.DescribeImagesInput, maxResults int, m imageMatcher) ([]types.Image, error) {
+	top := newTopImages(maxResults, m)
+	if err := describeImages(ctx, svc, input, top.add); err != nil {
+		return nil, err
+	}
+	return top.images(), nil
+}
@@ -0,0 +1,182 @@
+package finder
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+const testOwnerId = "123456789012"
+
+// fakePaginatedClient serves pages in the order given, the way DescribeImages returns images in no particular order
+type fakePaginatedClient struct {
+	pages [][]types.Image
+	calls int
+}
+
+func (f *fakePaginatedClient) DescribeImages(ctx context.Context, input *ec2.DescribeImagesInput, _ ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error) {
+	f.calls++
+
+	idx := 0
+	if token := aws.ToString(input.NextToken); token != "" {
+		if _, err := fmt.Sscanf(token, "page-%d", &idx); err != nil {
+			return nil, fmt.Errorf("unexpected NextToken %q", token)
+		}
+	}
+
+	out := &ec2.DescribeImagesOutput{Images: []types.Image{}}
+	if idx < len(f.pages) {
+		out.Images = f.pages[idx]
+	}
+	if idx+1 < len(f.pages) {
+		out.NextToken = aws.String(fmt.Sprintf("page-%d", idx+1))
+	}
+	return out, nil
+}
+
+func testImage(id, creationDate string) types.Image {
+	return types.Image{
+		ImageId:      aws.String(id),
+		Name:         aws.String("amazon-eks-node-al2023-x86_64-standard-1.35-v" + id),
+		OwnerId:      aws.String(testOwnerId),
+		CreationDate: aws.String(creationDate),
+	}
+}
+
+func testQuery(maxResults int) Query {
+	return Query{
+		Region:            "us-east-1",
+		OwnerId:           testOwnerId,
+		AmiType:           "AL2023_x86_64_STANDARD",
+		KubernetesVersion: "1.35",
+		MaxResults:        maxResults,
+	}
+}
+
+func imageIds(result *Result) []string {
+	ids := make([]string, 0, len(result.Images))
+	for _, i := range result.Images {
+		ids = append(ids, i.ImageId)
+	}
+	return ids
+}
+
+func TestFindTopImagesAcrossPages(t *testing.T) {
+	tests := []struct {
+		name       string
+		pages      [][]types.Image
+		maxResults int
+		want       []string
+	}{
+		{
+			name: "newest images spread across pages out of order",
+			pages: [][]types.Image{
+				{
+					testImage("ami-03", "2026-01-03T00:00:00.000Z"),
+					testImage("ami-09", "2026-01-09T00:00:00.000Z"),
+				},
+				{
+					testImage("ami-01", "2026-01-01T00:00:00.000Z"),
+					testImage("ami-07", "2026-01-07T00:00:00.000Z"),
+					testImage("ami-05", "2026-01-05T00:00:00.000Z"),
+				},
+				{
+					testImage("ami-08", "2026-01-08T00:00:00.000Z"),
+					testImage("ami-02", "2026-01-02T00:00:00.000Z"),
+				},
+			},
+			maxResults: 3,
+			want:       []string{"ami-09", "ami-08", "ami-07"},
+		},
+		{
+			name: "newest image on the last page",
+			pages: [][]types.Image{
+				{testImage("ami-01", "2026-01-01T00:00:00.000Z")},
+				{testImage("ami-02", "2026-01-02T00:00:00.000Z")},
+				{testImage("ami-10", "2026-01-10T00:00:00.000Z")},
+			},
+			maxResults: 1,
+			want:       []string{"ami-10"},
+		},
+		{
+			name: "ties on CreationDate are broken by ImageId",
+			pages: [][]types.Image{
+				{testImage("ami-0c", "2026-01-05T00:00:00.000Z")},
+				{
+					testImage("ami-0a", "2026-01-05T00:00:00.000Z"),
+					testImage("ami-01", "2026-01-01T00:00:00.000Z"),
+				},
+				{testImage("ami-0b", "2026-01-05T00:00:00.000Z")},
+			},
+			maxResults: 2,
+			want:       []string{"ami-0a", "ami-0b"},
+		},
+		{
+			name: "max results larger than the number of matches",
+			pages: [][]types.Image{
+				{testImage("ami-02", "2026-01-02T00:00:00.000Z")},
+				{
+					testImage("ami-03", "2026-01-03T00:00:00.000Z"),
+					testImage("ami-01", "2026-01-01T00:00:00.000Z"),
+				},
+			},
+			maxResults: 20,
+			want:       []string{"ami-03", "ami-02", "ami-01"},
+		},
+		{
+			name:       "no matches",
+			pages:      [][]types.Image{{}},
+			maxResults: 5,
+			want:       []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakePaginatedClient{pages: tt.pages}
+			result, err := New(client).Find(context.Background(), testQuery(tt.maxResults))
+			if err != nil {
+				t.Fatalf("Find() error = %v", err)
+			}
+			if got := imageIds(result); !slices.Equal(got, tt.want) {
+				t.Errorf("Find() images = %v, want %v", got, tt.want)
+			}
+			if client.calls != len(tt.pages) {
+				t.Errorf("DescribeImages called %d times, want every one of the %d pages", client.calls, len(tt.pages))
+			}
+		})
+	}
+}
+
+func TestFindTiesDoNotDependOnPageOrder(t *testing.T) {
+	images := []types.Image{
+		testImage("ami-0a", "2026-01-05T00:00:00.000Z"),
+		testImage("ami-0b", "2026-01-05T00:00:00.000Z"),
+		testImage("ami-0c", "2026-01-05T00:00:00.000Z"),
+	}
+
+	var want []string
+	for _, order := range [][]int{{0, 1, 2}, {2, 1, 0}, {1, 2, 0}} {
+		var pages [][]types.Image
+		for _, idx := range order {
+			pages = append(pages, []types.Image{images[idx]})
+		}
+
+		result, err := New(&fakePaginatedClient{pages: pages}).Find(context.Background(), testQuery(2))
+		if err != nil {
+			t.Fatalf("Find() error = %v", err)
+		}
+		got := imageIds(result)
+		if want == nil {
+			want = got
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("Find() with pages in order %v = %v, want %v", order, got, want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package finder
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+	"github.com/guessi/eks-ami-finder/pkg/owners"
+)
+
+// Identity is what could be learned about an AMI from its name
+type Identity struct {
+	AmiType           string
+	AutoMode          bool
+	KubernetesVersion string
+	ReleaseVersion    string
+}
+
+// Inspection is the reverse lookup result of a single AMI
+type Inspection struct {
+	Region     string
+	Image      Image
+	Identity   Identity
+	Identified bool // name matches one of the known EKS optimized AMI patterns
+	Official   bool // owner is the official account for the identified AMI type and region
+}
+
+type namePattern struct {
+	amiType  string
+	autoMode bool
+	re       *regexp.Regexp
+}
+
+// reversePatterns turns name pattern templates back into anchored regular expressions,
+// the first placeholder is the Kubernetes version and the trailing wildcard the release version.
+var reversePatterns = buildReversePatterns()
+
+func buildReversePatterns() []namePattern {
+	patterns := make([]namePattern, 0, len(constants.AmiTypes))
+	for _, t := range constants.AmiTypes {
+		parts := strings.Split(strings.TrimSuffix(t.NamePattern, "*"), "%s")
+		expr := "^" + regexp.QuoteMeta(parts[0]) + `(\d+\.\d+)` + regexp.QuoteMeta(parts[1]) + `(.+)$`
+		patterns = append(patterns, namePattern{
+			amiType:  t.Name,
+			autoMode: t.AutoMode,
+			re:       regexp.MustCompile(expr),
+		})
+	}
+	return patterns
+}
+
+// IdentifyName matches an AMI name against the known name patterns in reverse
+func IdentifyName(name string) (Identity, bool) {
+	for _, p := range reversePatterns {
+		if m := p.re.FindStringSubmatch(name); m != nil {
+			return Identity{
+				AmiType:           p.amiType,
+				AutoMode:          p.autoMode,
+				KubernetesVersion: m[1],
+				ReleaseVersion:    m[2],
+			}, true
+		}
+	}
+	return Identity{}, false
+}
+
+// OfficialOwnerId returns the publisher of the AMI type in the region according to the built-in owner mappings,
+// owner mapping overrides are never trusted to tell official AMIs.
+func OfficialOwnerId(id Identity, region string) (string, error) {
+	if v, ok := owners.ResolveBuiltin(owners.FamilyOf(id.AmiType, id.AutoMode), region); ok {
+		return v, nil
+	}
+	if id.AutoMode {
+		return "", newQueryError(ErrOwnerNotFound, "Auto Mode might not be supported in %s region", region)
+	}
+	return "", newQueryError(ErrOwnerNotFound, "unable to resolve official owner of %s in %s region", id.AmiType, region)
+}
+
+// IsOfficialOwner reports whether ownerId is the official publisher of the AMI type in the region, see OfficialOwnerId
+func IsOfficialOwner(id Identity, region, ownerId string) bool {
+	official, err := OfficialOwnerId(id, region)
+	return err == nil && official == ownerId
+}
+
+// Inspect looks up the given AMI IDs and identifies them, the client decides which region is queried.
+// IDs which don't exist in the region are left out.
+func (f *Finder) Inspect(ctx context.Context, region string, imageIds []string) ([]Inspection, error) {
+	if len(imageIds) == 0 {
+		return nil, nil
+	}
+
+	// Filter by image-id rather than ImageIds, which fails the whole request on any unknown ID
+	out, err := f.client.DescribeImages(ctx, &ec2.DescribeImagesInput{
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("image-id"),
+				Values: imageIds,
+			},
+		},
+		IncludeDeprecated: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, wrapAPIError(ctx, err)
+	}
+
+	inspections := make([]Inspection, 0, len(out.Images))
+	for _, i := range out.Images {
+		inspection := Inspection{
+			Region: region,
+			Image:  newImage(i),
+		}
+		inspection.Identity, inspection.Identified = IdentifyName(inspection.Image.Name)
+		if inspection.Identified {
+			inspection.Official = IsOfficialOwner(inspection.Identity, region, inspection.Image.OwnerId)
+		}
+		inspections = append(inspections, inspection)
+	}
+
+	// Keep the order of the requested IDs
+	slices.SortStableFunc(inspections, func(a, b Inspection) int {
+		return slices.Index(imageIds, a.Image.ImageId) - slices.Index(imageIds, b.Image.ImageId)
+	})
+
+	return inspections, nil
+}
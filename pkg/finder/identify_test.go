@@ -0,0 +1,130 @@
+package finder
+
+import (
+	"testing"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+)
+
+var identifyNameTests = []struct {
+	name        string
+	amiType     string
+	autoMode    bool
+	version     string
+	release     string
+	osFamily    string
+	arch        string
+	accelerator string
+	fips        bool
+}{
+	// Amazon Linux 2
+	{"amazon-eks-arm64-node-1.32-v20250519", "AL2_ARM_64", false, "1.32", "20250519", OSFamilyAL2, "arm64", "", false},
+	{"amazon-eks-node-1.32-v20250519", "AL2_x86_64", false, "1.32", "20250519", OSFamilyAL2, "x86_64", "", false},
+	{"amazon-eks-gpu-node-1.32-v20250519", "AL2_x86_64_GPU", false, "1.32", "20250519", OSFamilyAL2, "x86_64", AcceleratorNVIDIA, false},
+
+	// Amazon Linux 2023
+	{"amazon-eks-node-al2023-arm64-nvidia-1.35-v20260120", "AL2023_ARM_64_NVIDIA", false, "1.35", "20260120", OSFamilyAL2023, "arm64", AcceleratorNVIDIA, false},
+	{"amazon-eks-node-al2023-arm64-standard-1.35-v20260120", "AL2023_ARM_64_STANDARD", false, "1.35", "20260120", OSFamilyAL2023, "arm64", "", false},
+	{"amazon-eks-node-al2023-x86_64-neuron-1.35-v20260120", "AL2023_x86_64_NEURON", false, "1.35", "20260120", OSFamilyAL2023, "x86_64", AcceleratorNeuron, false},
+	{"amazon-eks-node-al2023-x86_64-nvidia-1.35-v20260120", "AL2023_x86_64_NVIDIA", false, "1.35", "20260120", OSFamilyAL2023, "x86_64", AcceleratorNVIDIA, false},
+	{"amazon-eks-node-al2023-x86_64-standard-1.35-v20260120", "AL2023_x86_64_STANDARD", false, "1.35", "20260120", OSFamilyAL2023, "x86_64", "", false},
+
+	// Bottlerocket
+	{"bottlerocket-aws-k8s-1.35-aarch64-v1.52.0-c9c6f9ff", "BOTTLEROCKET_ARM_64", false, "1.35", "1.52.0-c9c6f9ff", OSFamilyBottlerocket, "arm64", "", false},
+	{"bottlerocket-aws-k8s-1.35-fips-aarch64-v1.52.0-c9c6f9ff", "BOTTLEROCKET_ARM_64_FIPS", false, "1.35", "1.52.0-c9c6f9ff", OSFamilyBottlerocket, "arm64", "", true},
+	{"bottlerocket-aws-k8s-1.35-nvidia-aarch64-v1.52.0-c9c6f9ff", "BOTTLEROCKET_ARM_64_NVIDIA", false, "1.35", "1.52.0-c9c6f9ff", OSFamilyBottlerocket, "arm64", AcceleratorNVIDIA, false},
+	{"bottlerocket-aws-k8s-1.35-nvidia-fips-aarch64-v1.52.0-c9c6f9ff", "BOTTLEROCKET_ARM_64_NVIDIA_FIPS", false, "1.35", "1.52.0-c9c6f9ff", OSFamilyBottlerocket, "arm64", AcceleratorNVIDIA, true},
+	{"bottlerocket-aws-k8s-1.35-x86_64-v1.52.0-c9c6f9ff", "BOTTLEROCKET_x86_64", false, "1.35", "1.52.0-c9c6f9ff", OSFamilyBottlerocket, "x86_64", "", false},
+	{"bottlerocket-aws-k8s-1.35-fips-x86_64-v1.52.0-c9c6f9ff", "BOTTLEROCKET_x86_64_FIPS", false, "1.35", "1.52.0-c9c6f9ff", OSFamilyBottlerocket, "x86_64", "", true},
+	{"bottlerocket-aws-k8s-1.35-nvidia-x86_64-v1.52.0-c9c6f9ff", "BOTTLEROCKET_x86_64_NVIDIA", false, "1.35", "1.52.0-c9c6f9ff", OSFamilyBottlerocket, "x86_64", AcceleratorNVIDIA, false},
+	{"bottlerocket-aws-k8s-1.35-nvidia-fips-x86_64-v1.52.0-c9c6f9ff", "BOTTLEROCKET_x86_64_NVIDIA_FIPS", false, "1.35", "1.52.0-c9c6f9ff", OSFamilyBottlerocket, "x86_64", AcceleratorNVIDIA, true},
+
+	// Windows Server
+	{"Windows_Server-2016-English-Core-EKS_Optimized-1.32-2026.01.14", "WINDOWS_CORE_2016_x86_64", false, "1.32", "2026.01.14", OSFamilyWindows, "x86_64", "", false},
+	{"Windows_Server-2019-English-Core-EKS_Optimized-1.35-2026.01.14", "WINDOWS_CORE_2019_x86_64", false, "1.35", "2026.01.14", OSFamilyWindows, "x86_64", "", false},
+	{"Windows_Server-2022-English-Core-EKS_Optimized-1.35-2026.01.14", "WINDOWS_CORE_2022_x86_64", false, "1.35", "2026.01.14", OSFamilyWindows, "x86_64", "", false},
+	{"Windows_Server-2025-English-Core-EKS_Optimized-1.35-2026.01.14", "WINDOWS_CORE_2025_x86_64", false, "1.35", "2026.01.14", OSFamilyWindows, "x86_64", "", false},
+	{"Windows_Server-2016-English-Full-EKS_Optimized-1.32-2026.01.14", "WINDOWS_FULL_2016_x86_64", false, "1.32", "2026.01.14", OSFamilyWindows, "x86_64", "", false},
+	{"Windows_Server-2019-English-Full-EKS_Optimized-1.35-2026.01.14", "WINDOWS_FULL_2019_x86_64", false, "1.35", "2026.01.14", OSFamilyWindows, "x86_64", "", false},
+	{"Windows_Server-2022-English-Full-EKS_Optimized-1.35-2026.01.14", "WINDOWS_FULL_2022_x86_64", false, "1.35", "2026.01.14", OSFamilyWindows, "x86_64", "", false},
+	{"Windows_Server-2025-English-Full-EKS_Optimized-1.35-2026.01.14", "WINDOWS_FULL_2025_x86_64", false, "1.35", "2026.01.14", OSFamilyWindows, "x86_64", "", false},
+
+	// EKS Auto Mode
+	{"eks-auto-neuron-1.35-x86_64-20260120", "AUTO_MODE_NEURON_x86_64", true, "1.35", "20260120", OSFamilyBottlerocket, "x86_64", AcceleratorNeuron, false},
+	{"eks-auto-nvidia-1.35-aarch64-20260120", "AUTO_MODE_NVIDIA_ARM_64", true, "1.35", "20260120", OSFamilyBottlerocket, "arm64", AcceleratorNVIDIA, false},
+	{"eks-auto-nvidia-1.35-x86_64-20260120", "AUTO_MODE_NVIDIA_x86_64", true, "1.35", "20260120", OSFamilyBottlerocket, "x86_64", AcceleratorNVIDIA, false},
+	{"eks-auto-standard-1.35-aarch64-20260120", "AUTO_MODE_STANDARD_ARM_64", true, "1.35", "20260120", OSFamilyBottlerocket, "arm64", "", false},
+	{"eks-auto-standard-1.35-x86_64-20260120", "AUTO_MODE_STANDARD_x86_64", true, "1.35", "20260120", OSFamilyBottlerocket, "x86_64", "", false},
+}
+
+func TestIdentifyName(t *testing.T) {
+	for _, tt := range identifyNameTests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := IdentifyName(tt.name)
+			if !ok {
+				t.Fatalf("IdentifyName() did not identify %s", tt.name)
+			}
+			if id.AmiType != tt.amiType || id.AutoMode != tt.autoMode {
+				t.Errorf("IdentifyName() type = %s (auto mode %t), want %s (auto mode %t)", id.AmiType, id.AutoMode, tt.amiType, tt.autoMode)
+			}
+			if id.KubernetesVersion != tt.version {
+				t.Errorf("IdentifyName() version = %s, want %s", id.KubernetesVersion, tt.version)
+			}
+			if id.ReleaseVersion != tt.release {
+				t.Errorf("IdentifyName() release = %s, want %s", id.ReleaseVersion, tt.release)
+			}
+
+			m, ok := ParseMetadata(tt.name, "")
+			if !ok {
+				t.Fatalf("ParseMetadata() did not identify %s", tt.name)
+			}
+			if m.OSFamily != tt.osFamily || m.Architecture != tt.arch || m.Accelerator != tt.accelerator || m.FIPS != tt.fips {
+				t.Errorf("ParseMetadata() = %s/%s/%q/fips=%t, want %s/%s/%q/fips=%t",
+					m.OSFamily, m.Architecture, m.Accelerator, m.FIPS, tt.osFamily, tt.arch, tt.accelerator, tt.fips)
+			}
+		})
+	}
+}
+
+func TestIdentifyNameCoversRegistry(t *testing.T) {
+	for _, amiType := range constants.AmiTypes {
+		covered := false
+		for _, tt := range identifyNameTests {
+			if tt.amiType == amiType.Name && tt.autoMode == amiType.AutoMode {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			t.Errorf("no IdentifyName test case for %s (auto mode %t)", amiType.Name, amiType.AutoMode)
+		}
+	}
+}
+
+func TestIdentifyNameUnknown(t *testing.T) {
+	for _, name := range []string{
+		"",
+		"ubuntu-eks/k8s_1.35/images/hvm-ssd/ubuntu-jammy-22.04-amd64-server-20260120",
+		"amazon-eks-node-al2023-x86_64-standard-latest-v20260120",  // not a Kubernetes version
+		"amazon-eks-node-al2023-x86_64-standard-1.35-v",            // no release
+		"my-amazon-eks-node-al2023-x86_64-standard-1.35-v20260120", // look-alike prefix
+		"bottlerocket-aws-ecs-2-x86_64-v1.52.0-c9c6f9ff",
+	} {
+		if id, ok := IdentifyName(name); ok {
+			t.Errorf("IdentifyName(%q) = %+v, want no match", name, id)
+		}
+	}
+}
+
+func TestParseMetadataDescription(t *testing.T) {
+	m, ok := ParseMetadata(
+		"amazon-eks-node-al2023-x86_64-standard-1.35-v20260120",
+		"EKS-optimized Kubernetes node based on Amazon Linux 2023, (k8s: 1.35.0, containerd: 2.1.*)",
+	)
+	if !ok {
+		t.Fatal("ParseMetadata() did not identify the name")
+	}
+	if m.KubernetesPatch != "1.35.0" || m.ContainerdVersion != "2.1.*" || m.ReleaseDate != "20260120" {
+		t.Errorf("ParseMetadata() = patch %s, containerd %s, release date %s", m.KubernetesPatch, m.ContainerdVersion, m.ReleaseDate)
+	}
+}
@@ -0,0 +1,113 @@
+package finder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+)
+
+// Version used by Karpenter aliases to track the newest release
+const KarpenterAliasLatest = "latest"
+
+// Karpenter amiFamily names, see constants.AmiType
+const (
+	KarpenterFamilyAL2          = constants.KarpenterFamilyAL2
+	KarpenterFamilyAL2023       = constants.KarpenterFamilyAL2023
+	KarpenterFamilyBottlerocket = constants.KarpenterFamilyBottlerocket
+	KarpenterFamilyWindows2019  = constants.KarpenterFamilyWindows2019
+	KarpenterFamilyWindows2022  = constants.KarpenterFamilyWindows2022
+)
+
+// KarpenterAMIFamily maps an AMI type to the amiFamily of an EC2NodeClass
+func KarpenterAMIFamily(amiType string) (string, error) {
+	if t, ok := constants.LookupAmiType(amiType, false); ok && t.KarpenterFamily != "" {
+		return t.KarpenterFamily, nil
+	}
+	return "", fmt.Errorf("%s has no matching Karpenter amiFamily", amiType)
+}
+
+// aliasable reports whether Karpenter resolves the AMI type through aliases,
+// FIPS and Windows Full variants could only be selected by ID.
+func aliasable(amiType string) bool {
+	t, ok := constants.LookupAmiType(amiType, false)
+	return ok && t.KarpenterFamily != "" && t.KarpenterAlias
+}
+
+// KarpenterAlias returns the amiSelectorTerms alias pinning the release of an AMI, e.g. al2023@v20260120.
+// Windows aliases only support "latest".
+// - https://karpenter.sh/docs/concepts/nodeclasses/#specamiselectorterms
+func KarpenterAlias(m Metadata) (string, error) {
+	if m.AutoMode || !aliasable(m.AmiType) {
+		return "", fmt.Errorf("%s could not be selected by Karpenter alias, select it by ID instead", m.AmiType)
+	}
+
+	family, _ := KarpenterAMIFamily(m.AmiType)
+	version := KarpenterAliasLatest
+	switch family {
+	case KarpenterFamilyAL2, KarpenterFamilyAL2023:
+		if m.ReleaseVersion == "" {
+			return "", fmt.Errorf("unable to tell the release of %s", m.AmiType)
+		}
+		version = "v" + m.ReleaseVersion
+	case KarpenterFamilyBottlerocket:
+		// Release of Bottlerocket AMIs is the OS version followed by the build hash, e.g. 1.52.0-c9c6f9ff
+		release, _, _ := strings.Cut(m.ReleaseVersion, "-")
+		v, err := ParseSemver(release)
+		if err != nil {
+			return "", fmt.Errorf("unable to tell the release of %s", m.AmiType)
+		}
+		version = "v" + v.String()
+	}
+
+	return strings.ToLower(family) + "@" + version, nil
+}
+
+// KarpenterAliasQuery describes the AMIs a Karpenter alias resolves to
+type KarpenterAliasQuery struct {
+	AmiTypes            []string
+	ReleaseDate         string // set for Amazon Linux releases
+	BottlerocketVersion string // set for Bottlerocket releases
+}
+
+// ParseKarpenterAlias turns an alias like al2023@v20260120 or bottlerocket@latest into the AMI types
+// and release it selects. Karpenter picks the variant by instance type, so every aliasable type of the family is returned.
+func ParseKarpenterAlias(alias string) (KarpenterAliasQuery, error) {
+	family, version, ok := strings.Cut(strings.TrimSpace(alias), "@")
+	if !ok || family == "" || version == "" {
+		return KarpenterAliasQuery{}, fmt.Errorf("invalid alias '%s'. Expected format: <family>@<version> (e.g., al2023@v20260120)", alias)
+	}
+
+	var q KarpenterAliasQuery
+	for _, amiType := range ValidAmiTypes(false) {
+		if f, _ := KarpenterAMIFamily(amiType); strings.EqualFold(f, family) && aliasable(amiType) {
+			q.AmiTypes = append(q.AmiTypes, amiType)
+		}
+	}
+	if len(q.AmiTypes) == 0 {
+		return KarpenterAliasQuery{}, fmt.Errorf("unsupported alias family '%s'. Valid families: al2, al2023, bottlerocket, windows2019, windows2022", family)
+	}
+
+	if version == KarpenterAliasLatest {
+		return q, nil
+	}
+
+	switch strings.ToLower(family) {
+	case strings.ToLower(KarpenterFamilyAL2), strings.ToLower(KarpenterFamilyAL2023):
+		date := strings.TrimPrefix(version, "v")
+		if len(date) != 8 || strings.Trim(date, "0123456789") != "" {
+			return KarpenterAliasQuery{}, fmt.Errorf("invalid alias version '%s'. Expected format: vYYYYMMDD or latest", version)
+		}
+		q.ReleaseDate = date
+	case strings.ToLower(KarpenterFamilyBottlerocket):
+		v, err := ParseSemver(version)
+		if err != nil {
+			return KarpenterAliasQuery{}, fmt.Errorf("invalid alias version '%s'. Expected format: vX.Y.Z or latest", version)
+		}
+		q.BottlerocketVersion = v.String()
+	default:
+		return KarpenterAliasQuery{}, fmt.Errorf("invalid alias version '%s'. %s aliases only support latest", version, family)
+	}
+
+	return q, nil
+}
@@ -0,0 +1,121 @@
+package finder
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+)
+
+// Lifecycle statuses of Amazon EKS versions
+const (
+	LifecycleUpcoming  = "upcoming"    // listed but not released yet
+	LifecycleStandard  = "standard"    // under standard support
+	LifecycleExtended  = "extended"    // under extended support, at additional cost
+	LifecycleEndOfLife = "end-of-life" // no longer supported, AMIs are no longer published
+	LifecycleUnknown   = "unknown"     // newer than any version in the lifecycle table
+)
+
+// LifecycleDateLayout is the layout of the dates in the lifecycle table
+const LifecycleDateLayout = "2006-01-02"
+
+// Lifecycle is where an Amazon EKS version stands at a given time, dates are zero when unknown
+type Lifecycle struct {
+	Version              string
+	Status               string
+	ReleaseDate          time.Time
+	EndOfStandardSupport time.Time
+	EndOfExtendedSupport time.Time
+}
+
+func lifecycleDate(v string) time.Time {
+	t, _ := time.Parse(LifecycleDateLayout, v)
+	return t
+}
+
+// KubernetesLifecycle returns the lifecycle of the Kubernetes version at now,
+// versions older than the lifecycle table are past their end of life.
+func KubernetesLifecycle(kubernetesVersion string, now time.Time) Lifecycle {
+	l := Lifecycle{Version: kubernetesVersion, Status: LifecycleUnknown}
+
+	releases := constants.KubernetesReleases
+	if len(releases) > 0 && CompareKubernetesVersions(kubernetesVersion, releases[0].Version) < 0 {
+		l.Status = LifecycleEndOfLife
+		return l
+	}
+
+	for _, r := range releases {
+		if r.Version != kubernetesVersion {
+			continue
+		}
+		l.ReleaseDate = lifecycleDate(r.ReleaseDate)
+		l.EndOfStandardSupport = lifecycleDate(r.EndOfStandardSupport)
+		l.EndOfExtendedSupport = lifecycleDate(r.EndOfExtendedSupport)
+
+		switch {
+		case now.Before(l.ReleaseDate):
+			l.Status = LifecycleUpcoming
+		case now.Before(l.EndOfStandardSupport):
+			l.Status = LifecycleStandard
+		case now.Before(l.EndOfExtendedSupport):
+			l.Status = LifecycleExtended
+		default:
+			l.Status = LifecycleEndOfLife
+		}
+	}
+
+	return l
+}
+
+// KubernetesLifecycles returns the lifecycle of every version in the lifecycle table at now, oldest first
+func KubernetesLifecycles(now time.Time) []Lifecycle {
+	lifecycles := make([]Lifecycle, 0, len(constants.KubernetesReleases))
+	for _, r := range constants.KubernetesReleases {
+		lifecycles = append(lifecycles, KubernetesLifecycle(r.Version, now))
+	}
+	return lifecycles
+}
+
+// SupportedKubernetesVersions returns the Amazon EKS versions under standard or extended support at now, oldest first
+func SupportedKubernetesVersions(now time.Time) []string {
+	var versions []string
+	for _, l := range KubernetesLifecycles(now) {
+		if l.Supported() {
+			versions = append(versions, l.Version)
+		}
+	}
+	return versions
+}
+
+// LatestKubernetesVersion returns the newest Amazon EKS version released at now
+func LatestKubernetesVersion(now time.Time) string {
+	releases := constants.KubernetesReleases
+	for idx := len(releases) - 1; idx >= 0; idx-- {
+		if !now.Before(lifecycleDate(releases[idx].ReleaseDate)) {
+			return releases[idx].Version
+		}
+	}
+	return releases[len(releases)-1].Version
+}
+
+// Supported reports whether the version is under standard or extended support
+func (l Lifecycle) Supported() bool {
+	return l.Status == LifecycleStandard || l.Status == LifecycleExtended
+}
+
+// Warning describes why looking up AMIs of the version deserves attention, empty when it doesn't
+// - https://docs.aws.amazon.com/eks/latest/userguide/kubernetes-versions.html#version-deprecation
+func (l Lifecycle) Warning() string {
+	switch l.Status {
+	case LifecycleExtended:
+		return fmt.Sprintf("Amazon EKS %s is in extended support since %s, extended support ends on %s",
+			l.Version, l.EndOfStandardSupport.Format(LifecycleDateLayout), l.EndOfExtendedSupport.Format(LifecycleDateLayout))
+	case LifecycleEndOfLife:
+		if l.EndOfExtendedSupport.IsZero() {
+			return fmt.Sprintf("Amazon EKS %s reached end of life, new AMIs are no longer published", l.Version)
+		}
+		return fmt.Sprintf("Amazon EKS %s reached end of life on %s, new AMIs are no longer published",
+			l.Version, l.EndOfExtendedSupport.Format(LifecycleDateLayout))
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package finder
+
+import (
+	"regexp"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+)
+
+// OS families reported in Metadata
+const (
+	OSFamilyAL2          = constants.OSFamilyAL2
+	OSFamilyAL2023       = constants.OSFamilyAL2023
+	OSFamilyBottlerocket = constants.OSFamilyBottlerocket
+	OSFamilyWindows      = constants.OSFamilyWindows
+)
+
+// Accelerators reported in Metadata
+const (
+	AcceleratorNVIDIA = constants.AcceleratorNVIDIA
+	AcceleratorNeuron = constants.AcceleratorNeuron
+)
+
+var (
+	// Descriptions of Amazon Linux AMIs carry component versions, e.g.
+	// "EKS-optimized Kubernetes node based on Amazon Linux 2023, (k8s: 1.35.0, containerd: 2.1.*)"
+	descriptionKubernetesRe = regexp.MustCompile(`k8s: (\d+\.\d+\.\d+)`)
+	descriptionContainerdRe = regexp.MustCompile(`containerd: ([^,)\s]+)`)
+
+	// Release versions carrying a date, v20260120 (Amazon Linux) or 2026.01.14 (Windows)
+	releaseDateRe = regexp.MustCompile(`^v?(\d{4})\.?(\d{2})\.?(\d{2})`)
+)
+
+// Metadata is what could be learned about an AMI from its name and description
+type Metadata struct {
+	AmiType           string
+	AutoMode          bool
+	OSFamily          string
+	Architecture      string // arm64 or x86_64
+	Accelerator       string // empty for standard AMIs
+	FIPS              bool
+	KubernetesVersion string // minor version from the name, e.g. 1.35
+	KubernetesPatch   string // patch version from the description, e.g. 1.35.0
+	ReleaseVersion    string // release part of the name, e.g. 20260120 or 1.52.0-c9c6f9ff
+	ReleaseDate       string // yyyymmdd, empty when the release is not date based
+	ContainerdVersion string
+}
+
+// ParseMetadata extracts structured metadata from an AMI name and description,
+// ok is false when the name matches none of the known name patterns.
+func ParseMetadata(name, description string) (m Metadata, ok bool) {
+	if match := descriptionKubernetesRe.FindStringSubmatch(description); match != nil {
+		m.KubernetesPatch = match[1]
+	}
+	if match := descriptionContainerdRe.FindStringSubmatch(description); match != nil {
+		m.ContainerdVersion = match[1]
+	}
+
+	id, ok := IdentifyName(name)
+	if !ok {
+		return m, false
+	}
+
+	m.AmiType = id.AmiType
+	m.AutoMode = id.AutoMode
+	m.KubernetesVersion = id.KubernetesVersion
+	m.ReleaseVersion = id.ReleaseVersion
+	if match := releaseDateRe.FindStringSubmatch(id.ReleaseVersion); match != nil {
+		m.ReleaseDate = match[1] + match[2] + match[3]
+	}
+
+	// Everything else is known from the registry entry of the AMI type
+	if t, ok := constants.LookupAmiType(id.AmiType, id.AutoMode); ok {
+		m.OSFamily = t.OSFamily
+		m.Architecture = t.Architecture
+		m.Accelerator = t.Accelerator
+		m.FIPS = t.FIPS
+	}
+
+	return m, true
+}
@@ -0,0 +1,163 @@
+package finder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+	"github.com/guessi/eks-ami-finder/pkg/owners"
+)
+
+// DefaultMaxResults is the number of AMIs returned when the query sets none, the --max-results default as well
+const DefaultMaxResults = 20
+
+// Query describes which Amazon EKS optimized AMIs to look for
+type Query struct {
+	Region              string
+	OwnerId             string
+	AmiType             string
+	KubernetesVersion   string
+	ReleaseDate         string
+	Since               time.Time // inclusive lower bound of CreationDate, zero means unbounded
+	Until               time.Time // exclusive upper bound of CreationDate, zero means unbounded
+	BottlerocketVersion string    // exact version or constraint (e.g. ">=1.50.0 <1.52.0"), Bottlerocket only
+	MaxResults          int
+	AutoMode            bool
+	IncludeDeprecated   bool
+}
+
+// DefaultAmiType returns the AMI type assumed when none is specified
+func DefaultAmiType(autoMode bool) string {
+	if autoMode {
+		return "AUTO_MODE_STANDARD_x86_64"
+	}
+	return "AL2023_x86_64_STANDARD"
+}
+
+// Validate checks the AMI type, Kubernetes version and release date combination
+func (q Query) Validate() error {
+	if q.Region == "" {
+		return newQueryError(ErrInvalidRegion, "region must not be empty")
+	}
+
+	if err := ValidateKubernetesVersion(q.KubernetesVersion); err != nil {
+		return newQueryError(ErrUnsupportedVersion, "%v", err)
+	}
+
+	amiType, ok := constants.LookupAmiType(q.AmiType, q.AutoMode)
+	if !ok {
+		if q.AutoMode {
+			return newQueryError(ErrInvalidAmiType, "invalid --ami-type input for auto-mode (Valid input: %s)", strings.Join(ValidAmiTypes(true), ", "))
+		}
+		return newQueryError(ErrInvalidAmiType, "invalid --ami-type input (Valid input: %s)", strings.Join(ValidAmiTypes(false), ", "))
+	}
+
+	// Supported Kubernetes versions of each AMI type are listed in CompatibilityRules
+	if err := CompatibilityOf(q.AmiType, q.AutoMode).check(q.KubernetesVersion); err != nil {
+		return err
+	}
+
+	// Additional release date validation (requires AMI type context)
+	if releaseDate := q.ReleaseDate; len(releaseDate) != 0 {
+		if !isReleaseDate(releaseDate) {
+			return newQueryError(ErrInvalidReleaseDate, "invalid release-date format. Expected [yyyy], [yyyymm] or [yyyymmdd]")
+		}
+
+		// Amazon EKS was first released back at Jun 05, 2018
+		// - https://aws.amazon.com/blogs/aws/amazon-eks-now-generally-available/
+		if year, err := strconv.Atoi(releaseDate[:4]); err != nil || year < 2018 {
+			return newQueryError(ErrInvalidReleaseDate, "invalid release-date. Amazon EKS was first released in 2018")
+		}
+
+		// Bottlerocket AMIs don't support release date filtering
+		if !amiType.ReleaseFilter {
+			return newQueryError(ErrInvalidReleaseDate, "%s doesn't support filter by release date", amiType.OSFamily)
+		}
+	}
+
+	// CreationDate range is applied after retrieval, so it works for every AMI type
+	if !q.Since.IsZero() && !q.Until.IsZero() && !q.Since.Before(q.Until) {
+		return newQueryError(ErrInvalidDateRange, "invalid date range. since (%s) must be earlier than until (%s)", q.Since.Format(time.RFC3339), q.Until.Format(time.RFC3339))
+	}
+
+	// Bottlerocket versions are parsed from the AMI name, other AMI types don't carry one
+	if q.BottlerocketVersion != "" {
+		if !isBottlerocket(amiType) {
+			return newQueryError(ErrInvalidBottlerocketVersion, "bottlerocket-version only applies to Bottlerocket AMI types (you specified %s)", q.AmiType)
+		}
+		if _, err := ParseVersionConstraint(q.BottlerocketVersion); err != nil {
+			return newQueryError(ErrInvalidBottlerocketVersion, "invalid bottlerocket-version: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// isReleaseDate reports whether v is a [yyyy], [yyyymm] or [yyyymmdd] prefix of AMI release versions
+func isReleaseDate(v string) bool {
+	if len(v) != 4 && len(v) != 6 && len(v) != 8 {
+		return false
+	}
+	return strings.IndexFunc(v, func(r rune) bool { return r < '0' || r > '9' }) < 0
+}
+
+// Source reported for owners given explicitly in the query
+const OwnerSourceQuery = "owner-id"
+
+// ResolveOwnerId returns the owner to search for, see ResolveOwner
+func ResolveOwnerId(q Query) (string, error) {
+	ownerId, _, err := ResolveOwner(q)
+	return ownerId, err
+}
+
+// ResolveOwner returns the owner to search for and where it comes from, falls back to the official owner
+// of the region when no valid owner ID is given. Auto Mode AMIs are always looked up from the official owner.
+func ResolveOwner(q Query) (ownerId, source string, err error) {
+	family := owners.FamilyOf(q.AmiType, q.AutoMode)
+
+	if q.AutoMode {
+		if v, source, ok := owners.Resolve(family, q.Region); ok {
+			return v, source, nil
+		}
+		return "", "", newQueryError(ErrOwnerNotFound, "Auto Mode might not be supported in %s region", q.Region)
+	}
+
+	if owners.IsValidOwnerId(q.OwnerId) {
+		return q.OwnerId, OwnerSourceQuery, nil
+	}
+
+	if v, source, ok := owners.Resolve(family, q.Region); ok {
+		return v, source, nil
+	}
+
+	return "", "", newQueryError(ErrOwnerNotFound, "unable to resolve official owner of %s in %s region, please specify --owner-id", q.AmiType, q.Region)
+}
+
+// NamePattern returns the DescribeImages name filter for the query
+func NamePattern(q Query) (string, error) {
+	amiType, ok := constants.LookupAmiType(q.AmiType, q.AutoMode)
+	if !ok {
+		return "", newQueryError(ErrInvalidAmiType, "invalid ami-type input: %s", q.AmiType)
+	}
+
+	if amiType.ReleaseFilter {
+		return fmt.Sprintf(amiType.NamePattern, q.KubernetesVersion, q.ReleaseDate), nil
+	}
+
+	pattern := fmt.Sprintf(amiType.NamePattern, q.KubernetesVersion)
+	// Narrow down the name filter when looking for a single Bottlerocket release
+	if c, err := ParseVersionConstraint(q.BottlerocketVersion); err == nil && isBottlerocket(amiType) {
+		if v, ok := c.Exact(); ok {
+			pattern = strings.TrimSuffix(pattern, "*") + v.String() + "-*"
+		}
+	}
+	return pattern, nil
+}
+
+// isBottlerocket reports whether AMIs of the type are named after their Bottlerocket OS release,
+// Auto Mode AMIs run a variant of Bottlerocket but are named after their release date.
+func isBottlerocket(amiType constants.AmiType) bool {
+	return amiType.OSFamily == constants.OSFamilyBottlerocket && !amiType.AutoMode
+}
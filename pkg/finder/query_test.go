@@ -0,0 +1,45 @@
+package finder
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQueryValidateRejectsMalformedInput(t *testing.T) {
+	tests := []struct {
+		name              string
+		amiType           string
+		kubernetesVersion string
+		releaseDate       string
+		want              error
+	}{
+		{name: "valid", amiType: "AL2023_x86_64_STANDARD", kubernetesVersion: "1.35", releaseDate: "20260120"},
+		{name: "year only", amiType: "AL2023_x86_64_STANDARD", kubernetesVersion: "1.35", releaseDate: "2026"},
+		{name: "short release date", amiType: "AL2023_x86_64_STANDARD", kubernetesVersion: "1.35", releaseDate: "26", want: ErrInvalidReleaseDate},
+		{name: "non-numeric release date", amiType: "AL2023_x86_64_STANDARD", kubernetesVersion: "1.35", releaseDate: "2026ab", want: ErrInvalidReleaseDate},
+		{name: "non-numeric year", amiType: "AL2023_x86_64_STANDARD", kubernetesVersion: "1.35", releaseDate: "v2026", want: ErrInvalidReleaseDate},
+		{name: "release date before Amazon EKS", amiType: "AL2023_x86_64_STANDARD", kubernetesVersion: "1.35", releaseDate: "20170101", want: ErrInvalidReleaseDate},
+		{name: "empty version", amiType: "AL2023_x86_64_STANDARD", kubernetesVersion: "", want: ErrUnsupportedVersion},
+		{name: "junk version", amiType: "AL2023_x86_64_STANDARD", kubernetesVersion: "garbage", want: ErrUnsupportedVersion},
+		{name: "junk version without lower bound", amiType: "AL2_x86_64", kubernetesVersion: "garbage", want: ErrUnsupportedVersion},
+		{name: "version before Amazon EKS", amiType: "AL2_x86_64", kubernetesVersion: "1.9", want: ErrUnsupportedVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Query{
+				Region:            "us-east-1",
+				AmiType:           tt.amiType,
+				KubernetesVersion: tt.kubernetesVersion,
+				ReleaseDate:       tt.releaseDate,
+			}.Validate()
+
+			switch {
+			case tt.want == nil && err != nil:
+				t.Errorf("Validate() error = %v, want nil", err)
+			case tt.want != nil && !errors.Is(err, tt.want):
+				t.Errorf("Validate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
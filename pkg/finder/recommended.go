@@ -0,0 +1,125 @@
+package finder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+	"github.com/guessi/eks-ami-finder/pkg/owners"
+)
+
+// Source reported for owners of recommended AMIs, which are taken from the image itself
+const OwnerSourceRecommended = "recommended"
+
+// Path segments of the recommended AMI parameters for Amazon Linux and Bottlerocket
+// - https://docs.aws.amazon.com/eks/latest/userguide/retrieve-ami-id.html
+// - https://docs.aws.amazon.com/eks/latest/userguide/retrieve-ami-id-bottlerocket.html
+var recommendedParameterPaths = map[string]string{
+	"AL2_ARM_64":                      "/aws/service/eks/optimized-ami/%s/amazon-linux-2-arm64/recommended/image_id",
+	"AL2_x86_64_GPU":                  "/aws/service/eks/optimized-ami/%s/amazon-linux-2-gpu/recommended/image_id",
+	"AL2_x86_64":                      "/aws/service/eks/optimized-ami/%s/amazon-linux-2/recommended/image_id",
+	"AL2023_ARM_64_NVIDIA":            "/aws/service/eks/optimized-ami/%s/amazon-linux-2023/arm64/nvidia/recommended/image_id",
+	"AL2023_ARM_64_STANDARD":          "/aws/service/eks/optimized-ami/%s/amazon-linux-2023/arm64/standard/recommended/image_id",
+	"AL2023_x86_64_NEURON":            "/aws/service/eks/optimized-ami/%s/amazon-linux-2023/x86_64/neuron/recommended/image_id",
+	"AL2023_x86_64_NVIDIA":            "/aws/service/eks/optimized-ami/%s/amazon-linux-2023/x86_64/nvidia/recommended/image_id",
+	"AL2023_x86_64_STANDARD":          "/aws/service/eks/optimized-ami/%s/amazon-linux-2023/x86_64/standard/recommended/image_id",
+	"BOTTLEROCKET_ARM_64_FIPS":        "/aws/service/bottlerocket/aws-k8s-%s-fips/arm64/latest/image_id",
+	"BOTTLEROCKET_ARM_64_NVIDIA":      "/aws/service/bottlerocket/aws-k8s-%s-nvidia/arm64/latest/image_id",
+	"BOTTLEROCKET_ARM_64_NVIDIA_FIPS": "/aws/service/bottlerocket/aws-k8s-%s-nvidia-fips/arm64/latest/image_id",
+	"BOTTLEROCKET_ARM_64":             "/aws/service/bottlerocket/aws-k8s-%s/arm64/latest/image_id",
+	"BOTTLEROCKET_x86_64_FIPS":        "/aws/service/bottlerocket/aws-k8s-%s-fips/x86_64/latest/image_id",
+	"BOTTLEROCKET_x86_64_NVIDIA":      "/aws/service/bottlerocket/aws-k8s-%s-nvidia/x86_64/latest/image_id",
+	"BOTTLEROCKET_x86_64_NVIDIA_FIPS": "/aws/service/bottlerocket/aws-k8s-%s-nvidia-fips/x86_64/latest/image_id",
+	"BOTTLEROCKET_x86_64":             "/aws/service/bottlerocket/aws-k8s-%s/x86_64/latest/image_id",
+}
+
+// RecommendedParameterName returns the SSM public parameter holding the recommended AMI ID
+func RecommendedParameterName(amiType, kubernetesVersion string, autoMode bool) (string, error) {
+	if autoMode {
+		return "", newQueryError(ErrInvalidAmiType, "recommended AMI is not published for Auto Mode")
+	}
+
+	if path, ok := recommendedParameterPaths[amiType]; ok {
+		return fmt.Sprintf(path, kubernetesVersion), nil
+	}
+
+	// Windows parameters are named after the AMI name
+	// - https://docs.aws.amazon.com/eks/latest/userguide/retrieve-windows-ami-id.html
+	if t, ok := constants.LookupAmiType(amiType, false); ok && t.OSFamily == constants.OSFamilyWindows {
+		name := strings.TrimSuffix(fmt.Sprintf(t.NamePattern, kubernetesVersion, ""), "-*")
+		return fmt.Sprintf("/aws/service/ami-windows-latest/%s/image_id", name), nil
+	}
+
+	return "", newQueryError(ErrInvalidAmiType, "invalid ami-type input: %s", amiType)
+}
+
+// FindRecommended resolves the AMI currently recommended by AWS through SSM public parameters,
+// then enriches it with DescribeImages. The SSM client must be configured for the same region.
+func (f *Finder) FindRecommended(ctx context.Context, ssmSvc owners.SSMGetParameterAPIClient, q Query) (*Result, error) {
+	if q.AmiType == "" {
+		q.AmiType = DefaultAmiType(q.AutoMode)
+	}
+
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	if q.ReleaseDate != "" {
+		return nil, newQueryError(ErrInvalidReleaseDate, "release-date can not be combined with recommended AMI lookup")
+	}
+	if !q.Since.IsZero() || !q.Until.IsZero() {
+		return nil, newQueryError(ErrInvalidDateRange, "since and until can not be combined with recommended AMI lookup")
+	}
+	if q.BottlerocketVersion != "" {
+		return nil, newQueryError(ErrInvalidBottlerocketVersion, "bottlerocket-version can not be combined with recommended AMI lookup")
+	}
+
+	name, err := RecommendedParameterName(q.AmiType, q.KubernetesVersion, q.AutoMode)
+	if err != nil {
+		return nil, err
+	}
+
+	param, err := ssmSvc.GetParameter(ctx, &ssm.GetParameterInput{Name: aws.String(name)})
+	if err != nil {
+		var notFound *ssmtypes.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return nil, newQueryError(ErrNotFound, "no recommended AMI published for %s with Amazon EKS %s in %s region", q.AmiType, q.KubernetesVersion, q.Region)
+		}
+		return nil, wrapAPIError(ctx, err)
+	}
+
+	var imageId string
+	if param.Parameter != nil {
+		imageId = aws.ToString(param.Parameter.Value)
+	}
+	if imageId == "" || imageId == "None" {
+		return nil, newQueryError(ErrNotFound, "no recommended AMI published for %s with Amazon EKS %s in %s region", q.AmiType, q.KubernetesVersion, q.Region)
+	}
+
+	out, err := f.client.DescribeImages(ctx, &ec2.DescribeImagesInput{
+		ImageIds:          []string{imageId},
+		IncludeDeprecated: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, wrapAPIError(ctx, err)
+	}
+
+	result := &Result{
+		Region:      q.Region,
+		OwnerSource: OwnerSourceRecommended,
+		Parameter:   name,
+	}
+	for _, i := range out.Images {
+		image := newImage(i)
+		image.Recommended = true
+		result.OwnerId = image.OwnerId
+		result.Images = append(result.Images, image)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,93 @@
+package finder
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+type fakeSSM struct {
+	out  *ssm.GetParameterOutput
+	err  error
+	name string // name of the last parameter looked up
+}
+
+func (f *fakeSSM) GetParameter(ctx context.Context, params *ssm.GetParameterInput, _ ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	f.name = aws.ToString(params.Name)
+	return f.out, f.err
+}
+
+func TestFindRecommended(t *testing.T) {
+	tests := []struct {
+		name    string
+		out     *ssm.GetParameterOutput
+		err     error
+		want    string
+		wantErr error
+	}{
+		{
+			name: "published",
+			out:  &ssm.GetParameterOutput{Parameter: &ssmtypes.Parameter{Value: aws.String("ami-01")}},
+			want: "ami-01",
+		},
+		{
+			name:    "nil parameter",
+			out:     &ssm.GetParameterOutput{},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "nil value",
+			out:     &ssm.GetParameterOutput{Parameter: &ssmtypes.Parameter{}},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "empty value",
+			out:     &ssm.GetParameterOutput{Parameter: &ssmtypes.Parameter{Value: aws.String("")}},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "None",
+			out:     &ssm.GetParameterOutput{Parameter: &ssmtypes.Parameter{Value: aws.String("None")}},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "parameter not found",
+			err:     &ssmtypes.ParameterNotFound{},
+			wantErr: ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ssmSvc := &fakeSSM{out: tt.out, err: tt.err}
+			client := &fakePaginatedClient{pages: [][]types.Image{{testImage("ami-01", "2026-01-20T00:00:00.000Z")}}}
+
+			result, err := New(client).FindRecommended(context.Background(), ssmSvc, testQuery(1))
+			if want := "/aws/service/eks/optimized-ami/1.35/amazon-linux-2023/x86_64/standard/recommended/image_id"; ssmSvc.name != want {
+				t.Errorf("parameter = %q, want %q", ssmSvc.name, want)
+			}
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("FindRecommended() error = %v, want %v", err, tt.wantErr)
+				}
+				if client.calls != 0 {
+					t.Errorf("DescribeImages called %d times, want none", client.calls)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("FindRecommended() error = %v", err)
+			}
+			if len(result.Images) != 1 || result.Images[0].ImageId != tt.want || !result.Images[0].Recommended {
+				t.Errorf("FindRecommended() images = %+v, want recommended %s", result.Images, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package finder
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/guessi/eks-ami-finder/pkg/owners"
+)
+
+// Verification statuses, only VerificationPassed passes
+const (
+	VerificationPassed = "pass"
+	VerificationFailed = "fail"
+
+	// The owner is not official, but an owner mapping override trusts it
+	VerificationOverride = "override"
+)
+
+// Verification tells whether an AMI is an official Amazon EKS optimized AMI
+type Verification struct {
+	ImageId         string
+	Found           bool
+	Inspection      Inspection
+	ExpectedOwnerId string // official owner according to the built-in owner mappings
+	OverrideSource  string // owner mapping file trusting the owner, when the built-in owner mappings don't
+	Problems        []string
+}
+
+func (v Verification) Passed() bool { return len(v.Problems) == 0 }
+
+// Status returns VerificationPassed, VerificationOverride or VerificationFailed
+func (v Verification) Status() string {
+	switch {
+	case v.Passed():
+		return VerificationPassed
+	case v.OverrideSource != "" && len(v.Problems) == 1:
+		return VerificationOverride
+	}
+	return VerificationFailed
+}
+
+// Verify checks each AMI is published by the official owner of its family and region according to
+// the built-in owner mappings, and that its name fits one of the known name patterns. Look-alike AMIs
+// fail the check, owners only trusted by an owner mapping override never pass it.
+func (f *Finder) Verify(ctx context.Context, region string, imageIds []string) ([]Verification, error) {
+	inspections, err := f.Inspect(ctx, region, imageIds)
+	if err != nil {
+		return nil, err
+	}
+
+	verifications := make([]Verification, 0, len(imageIds))
+	for _, id := range imageIds {
+		v := Verification{ImageId: id}
+
+		idx := slices.IndexFunc(inspections, func(i Inspection) bool { return i.Image.ImageId == id })
+		if idx < 0 {
+			v.Problems = append(v.Problems, fmt.Sprintf("AMI not found or not accessible in %s region", region))
+			verifications = append(verifications, v)
+			continue
+		}
+
+		v.Found = true
+		v.Inspection = inspections[idx]
+
+		if !v.Inspection.Identified {
+			v.Problems = append(v.Problems, "name does not match any known Amazon EKS optimized AMI pattern")
+			verifications = append(verifications, v)
+			continue
+		}
+
+		id, ownerId := v.Inspection.Identity, v.Inspection.Image.OwnerId
+		expected, err := OfficialOwnerId(id, region)
+		v.ExpectedOwnerId = expected
+		switch {
+		case err == nil && ownerId == expected:
+			verifications = append(verifications, v)
+			continue
+		case err != nil:
+			v.Problems = append(v.Problems, err.Error())
+		default:
+			v.Problems = append(v.Problems, fmt.Sprintf("owner %s is not the official owner %s of %s in %s region", ownerId, expected, id.AmiType, region))
+		}
+
+		// Reported apart so users of an override file could tell their own accounts from look-alikes
+		if override, source, ok := owners.ResolveOverride(owners.FamilyOf(id.AmiType, id.AutoMode), region); ok && override == ownerId {
+			v.OverrideSource = source
+		}
+
+		verifications = append(verifications, v)
+	}
+
+	return verifications, nil
+}
@@ -0,0 +1,88 @@
+package finder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/guessi/eks-ami-finder/pkg/owners"
+)
+
+func loadTestOverrides(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "owners.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := owners.LoadOverrides(path); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		empty := filepath.Join(t.TempDir(), "empty.yaml")
+		if err := os.WriteFile(empty, []byte("{}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := owners.LoadOverrides(empty); err != nil {
+			t.Fatal(err)
+		}
+	})
+	return path
+}
+
+func TestVerifyTrustsBuiltinOwnersOnly(t *testing.T) {
+	const region = "us-east-1"
+
+	official, ok := owners.ResolveBuiltin(owners.FamilyOf("AL2023_x86_64_STANDARD", false), region)
+	if !ok {
+		t.Fatalf("no built-in owner of AL2023_x86_64_STANDARD in %s", region)
+	}
+	source := loadTestOverrides(t, "AmazonLinux:\n  "+region+": \""+testOwnerId+"\"\n")
+
+	image := func(id, ownerId string) types.Image {
+		i := testImage(id, "2026-01-01T00:00:00.000Z")
+		i.OwnerId = aws.String(ownerId)
+		return i
+	}
+	client := &fakePaginatedClient{pages: [][]types.Image{{
+		image("ami-official", official),
+		image("ami-override", testOwnerId),
+		image("ami-lookalike", "210987654321"),
+	}}}
+
+	verifications, err := New(client).Verify(context.Background(), region, []string{"ami-official", "ami-override", "ami-lookalike"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		status         string
+		official       bool
+		overrideSource string
+	}{
+		{VerificationPassed, true, ""},
+		{VerificationOverride, false, source},
+		{VerificationFailed, false, ""},
+	}
+	for idx, tt := range tests {
+		v := verifications[idx]
+		if got := v.Status(); got != tt.status {
+			t.Errorf("%s: Status() = %q, want %q (problems: %v)", v.ImageId, got, tt.status, v.Problems)
+		}
+		if v.Passed() != (tt.status == VerificationPassed) {
+			t.Errorf("%s: Passed() = %v with status %q", v.ImageId, v.Passed(), tt.status)
+		}
+		if v.ExpectedOwnerId != official {
+			t.Errorf("%s: ExpectedOwnerId = %q, want the built-in owner %q", v.ImageId, v.ExpectedOwnerId, official)
+		}
+		if v.Inspection.Official != tt.official {
+			t.Errorf("%s: Official = %v, want %v", v.ImageId, v.Inspection.Official, tt.official)
+		}
+		if v.OverrideSource != tt.overrideSource {
+			t.Errorf("%s: OverrideSource = %q, want %q", v.ImageId, v.OverrideSource, tt.overrideSource)
+		}
+	}
+}
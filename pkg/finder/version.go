@@ -0,0 +1,109 @@
+package finder
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+)
+
+const (
+	KubernetesVersionLatest    = "latest"
+	KubernetesVersionSupported = "supported"
+)
+
+func parseKubernetesVersion(v string) (major, minor int, err error) {
+	parts := strings.Split(v, ".")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid Kubernetes version format. Expected format: X.Y (e.g., 1.35)")
+	}
+
+	major, err1 := strconv.Atoi(parts[0])
+	minor, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil {
+		return 0, 0, fmt.Errorf("invalid Kubernetes version format. Expected format: X.Y (e.g., 1.35)")
+	}
+
+	return major, minor, nil
+}
+
+// ValidateKubernetesVersion checks v is a single X.Y version which could exist on Amazon EKS
+func ValidateKubernetesVersion(v string) error {
+	major, minor, err := parseKubernetesVersion(v)
+	if err != nil {
+		return err
+	}
+
+	// The first Amazon EKS version was 1.10
+	// - https://aws.amazon.com/blogs/aws/amazon-eks-now-generally-available/
+	if major != 1 || minor < 10 {
+		return fmt.Errorf("the very first Amazon EKS version was 1.10, so there would have no Amazon EKS %s", v)
+	}
+
+	return nil
+}
+
+// CompareKubernetesVersions compares two X.Y versions numerically, invalid versions sort first
+func CompareKubernetesVersions(a, b string) int {
+	aMajor, aMinor, _ := parseKubernetesVersion(a)
+	bMajor, bMinor, _ := parseKubernetesVersion(b)
+	if aMajor != bMajor {
+		return aMajor - bMajor
+	}
+	return aMinor - bMinor
+}
+
+// ExpandKubernetesVersions expands a comma-separated list of versions, ranges (1.32-1.35)
+// and keywords ("latest", "supported") into a sorted list of X.Y versions. Ranges may not
+// go past the newest version of the lifecycle table.
+func ExpandKubernetesVersions(spec string) ([]string, error) {
+	now := time.Now()
+
+	var versions []string
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		switch {
+		case item == "":
+			continue
+		case item == KubernetesVersionLatest:
+			versions = append(versions, LatestKubernetesVersion(now))
+		case item == KubernetesVersionSupported:
+			versions = append(versions, SupportedKubernetesVersions(now)...)
+		case strings.Contains(item, "-"):
+			lo, hi, _ := strings.Cut(item, "-")
+			if err := ValidateKubernetesVersion(lo); err != nil {
+				return nil, err
+			}
+			if err := ValidateKubernetesVersion(hi); err != nil {
+				return nil, err
+			}
+			if CompareKubernetesVersions(lo, hi) > 0 {
+				return nil, fmt.Errorf("invalid Kubernetes version range %s, lower bound is greater than upper bound", item)
+			}
+			// Ranges stop at the lifecycle table, newer versions could still be listed one by one
+			if newest := constants.KubernetesReleases[len(constants.KubernetesReleases)-1].Version; CompareKubernetesVersions(hi, newest) > 0 {
+				return nil, fmt.Errorf("invalid Kubernetes version range %s, upper bound is newer than the newest known Amazon EKS version %s", item, newest)
+			}
+			_, loMinor, _ := parseKubernetesVersion(lo)
+			_, hiMinor, _ := parseKubernetesVersion(hi)
+			for minor := loMinor; minor <= hiMinor; minor++ {
+				versions = append(versions, fmt.Sprintf("1.%d", minor))
+			}
+		default:
+			if err := ValidateKubernetesVersion(item); err != nil {
+				return nil, err
+			}
+			versions = append(versions, item)
+		}
+	}
+
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("kubernetes-version must not be empty")
+	}
+
+	slices.SortFunc(versions, CompareKubernetesVersions)
+	return slices.Compact(versions), nil
+}
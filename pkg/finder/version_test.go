@@ -0,0 +1,49 @@
+package finder
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+)
+
+func TestExpandKubernetesVersionsRanges(t *testing.T) {
+	newest := constants.KubernetesReleases[len(constants.KubernetesReleases)-1].Version
+	_, newestMinor, _ := parseKubernetesVersion(newest)
+	next := fmt.Sprintf("1.%d", newestMinor+1)
+
+	tests := []struct {
+		spec    string
+		want    []string
+		wantErr string
+	}{
+		{spec: "1.32-1.35", want: []string{"1.32", "1.33", "1.34", "1.35"}},
+		{spec: "1.33,1.32-1.33", want: []string{"1.32", "1.33"}},
+		{spec: "1.10-1.12", want: []string{"1.10", "1.11", "1.12"}},
+		{spec: newest + "-" + newest, want: []string{newest}},
+		{spec: next, want: []string{next}},
+		{spec: "1.35-1.32", wantErr: "lower bound is greater than upper bound"},
+		{spec: "1.0-1.9999", wantErr: "very first Amazon EKS version"},
+		{spec: "1.10-1.9999", wantErr: "newer than the newest known Amazon EKS version"},
+		{spec: newest + "-" + next, wantErr: "newer than the newest known Amazon EKS version"},
+	}
+
+	for _, tt := range tests {
+		got, err := ExpandKubernetesVersions(tt.spec)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ExpandKubernetesVersions(%q) error = %v, want %q", tt.spec, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ExpandKubernetesVersions(%q) unexpected error: %v", tt.spec, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ExpandKubernetesVersions(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
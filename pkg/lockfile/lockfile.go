@@ -0,0 +1,125 @@
+// Package lockfile reads and writes eks-ami.lock, which pins Amazon EKS optimized AMIs per region and AMI type.
+package lockfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultPath is the lockfile looked up when none is specified
+const DefaultPath = "eks-ami.lock"
+
+// SchemaVersion is only bumped on breaking changes of the file layout
+const SchemaVersion = "v1"
+
+const header = "# Generated by eks-ami-finder, update with \"eks-ami-finder lock update\"\n"
+
+// Entry pins one AMI together with the query it was resolved from
+type Entry struct {
+	Region              string `json:"region" yaml:"region"`
+	AmiType             string `json:"amiType" yaml:"amiType"`
+	AutoMode            bool   `json:"autoMode,omitempty" yaml:"autoMode,omitempty"`
+	KubernetesVersion   string `json:"kubernetesVersion" yaml:"kubernetesVersion"`
+	ReleaseDate         string `json:"releaseDate,omitempty" yaml:"releaseDate,omitempty"`
+	BottlerocketVersion string `json:"bottlerocketVersion,omitempty" yaml:"bottlerocketVersion,omitempty"`
+	Recommended         bool   `json:"recommended,omitempty" yaml:"recommended,omitempty"`
+	QueryOwnerId        string `json:"queryOwnerId,omitempty" yaml:"queryOwnerId,omitempty"` // --owner-id, empty for the official owner
+	Since               string `json:"since,omitempty" yaml:"since,omitempty"`               // --since as given, relative ones are resolved on each update
+	Until               string `json:"until,omitempty" yaml:"until,omitempty"`               // --until as given, relative ones are resolved on each update
+	IncludeDeprecated   bool   `json:"includeDeprecated,omitempty" yaml:"includeDeprecated,omitempty"`
+
+	ImageId      string `json:"imageId" yaml:"imageId"`
+	Name         string `json:"name" yaml:"name"`
+	OwnerId      string `json:"ownerId" yaml:"ownerId"`
+	CreationDate string `json:"creationDate" yaml:"creationDate"`
+}
+
+// Key identifies the slot of an entry, at most one AMI is pinned per key
+func (e Entry) Key() string {
+	key := strings.Join([]string{e.Region, e.AmiType, e.KubernetesVersion}, "/")
+	if e.AutoMode {
+		key += "/auto-mode"
+	}
+	return key
+}
+
+// File is the content of a lockfile
+type File struct {
+	SchemaVersion string  `json:"schemaVersion" yaml:"schemaVersion"`
+	Entries       []Entry `json:"entries" yaml:"entries"`
+}
+
+// New returns an empty lockfile
+func New() *File {
+	return &File{SchemaVersion: SchemaVersion, Entries: []Entry{}}
+}
+
+// Load reads the lockfile at path, a missing file is returned as os.ErrNotExist
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("lockfile %s not found, create it with \"eks-ami-finder lock\": %w", path, err)
+		}
+		return nil, fmt.Errorf("unable to read lockfile %s: %v", path, err)
+	}
+
+	var f File
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("unable to parse lockfile %s: %v", path, err)
+	}
+	if f.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("unsupported lockfile schemaVersion '%s' in %s, expected %s", f.SchemaVersion, path, SchemaVersion)
+	}
+
+	for idx, e := range f.Entries {
+		if e.Region == "" || e.AmiType == "" || e.KubernetesVersion == "" || e.ImageId == "" {
+			return nil, fmt.Errorf("invalid entry #%d in %s, region, amiType, kubernetesVersion and imageId are required", idx+1, path)
+		}
+	}
+
+	return &f, nil
+}
+
+// Save writes the lockfile to path with entries in a stable order
+func (f *File) Save(path string) error {
+	f.SchemaVersion = SchemaVersion
+	f.sort()
+
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(f); err != nil {
+		return fmt.Errorf("unable to encode lockfile: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("unable to encode lockfile: %v", err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("unable to write lockfile %s: %v", path, err)
+	}
+	return nil
+}
+
+// Upsert adds the entry, or replaces the entry with the same key
+func (f *File) Upsert(e Entry) {
+	if idx := slices.IndexFunc(f.Entries, func(o Entry) bool { return o.Key() == e.Key() }); idx >= 0 {
+		f.Entries[idx] = e
+		return
+	}
+	f.Entries = append(f.Entries, e)
+}
+
+func (f *File) sort() {
+	slices.SortStableFunc(f.Entries, func(a, b Entry) int {
+		return strings.Compare(a.Key(), b.Key())
+	})
+}
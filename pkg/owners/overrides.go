@@ -0,0 +1,107 @@
+package owners
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Source reported for owners coming from the compiled-in mapping tables
+const SourceBuiltin = "built-in"
+
+var (
+	overridesMu     sync.RWMutex
+	overrides       map[string]map[string]string
+	overridesSource string
+)
+
+// LoadOverrides reads an owner mapping file and merges it over the built-in mapping tables.
+// The file is keyed by family then region, in YAML or JSON (which is valid YAML as well):
+//
+//	Bottlerocket:
+//	  ap-southeast-7: "058264547253"
+func LoadOverrides(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read owner mappings: %v", err)
+	}
+
+	var parsed map[string]map[string]string
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return fmt.Errorf("unable to parse owner mappings %s: %v", path, err)
+	}
+
+	for family, mappings := range parsed {
+		if !slices.Contains(Families, family) {
+			return fmt.Errorf("invalid family '%s' in owner mappings %s. Valid families: %s", family, path, strings.Join(Families, ", "))
+		}
+		for region, ownerId := range mappings {
+			if region == "" {
+				return fmt.Errorf("empty region for %s in owner mappings %s", family, path)
+			}
+			if !IsValidOwnerId(ownerId) {
+				return fmt.Errorf("owner of %s in %s region must be a 12-digit AWS account ID, got '%s' (%s)", family, region, ownerId, path)
+			}
+		}
+	}
+
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+	overrides = parsed
+	overridesSource = path
+
+	return nil
+}
+
+// Resolve returns the official owner of the family in the region and where it comes from.
+// Region specific entries win over the "*" fallback, overrides win over built-in mappings.
+func Resolve(family, region string) (ownerId, source string, ok bool) {
+	builtin, err := BuiltinMappings(family)
+	if err != nil {
+		return "", "", false
+	}
+
+	overridesMu.RLock()
+	defer overridesMu.RUnlock()
+	override := overrides[family]
+
+	if v, ok := override[region]; ok {
+		return v, overridesSource, true
+	}
+	if v, ok := builtin[region]; ok {
+		return v, SourceBuiltin, true
+	}
+	if v, ok := override["*"]; ok {
+		return v, overridesSource, true
+	}
+	if v, ok := builtin["*"]; ok {
+		return v, SourceBuiltin, true
+	}
+
+	return "", "", false
+}
+
+// ResolveBuiltin returns the official owner of the family in the region from the compiled-in mapping tables only,
+// overrides are left out since anyone could point them to their own accounts.
+func ResolveBuiltin(family, region string) (string, bool) {
+	builtin, err := BuiltinMappings(family)
+	if err != nil {
+		return "", false
+	}
+	return LookupOwner(builtin, region)
+}
+
+// ResolveOverride returns the owner of the family in the region from the owner mapping file alone and its path
+func ResolveOverride(family, region string) (ownerId, source string, ok bool) {
+	overridesMu.RLock()
+	defer overridesMu.RUnlock()
+
+	if v, ok := LookupOwner(overrides[family], region); ok {
+		return v, overridesSource, true
+	}
+	return "", "", false
+}
@@ -0,0 +1,199 @@
+// Package owners resolves the AWS accounts publishing Amazon EKS optimized AMIs,
+// it is the Go counterpart of hack/ami-owner-info-check.sh.
+package owners
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/format"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/guessi/eks-ami-finder/pkg/constants"
+)
+
+const (
+	FamilyAmazonLinux  string = constants.OwnerFamilyAmazonLinux
+	FamilyBottlerocket string = constants.OwnerFamilyBottlerocket
+	FamilyWindows      string = constants.OwnerFamilyWindows
+	FamilyAutoMode     string = constants.OwnerFamilyAutoMode
+)
+
+var (
+	// Families with an owner mapping table
+	Families = []string{
+		FamilyAmazonLinux,
+		FamilyBottlerocket,
+		FamilyWindows,
+		FamilyAutoMode,
+	}
+
+	// Families which could be discovered through SSM public parameters
+	DiscoverableFamilies = []string{
+		FamilyAmazonLinux,
+		FamilyBottlerocket,
+		FamilyWindows,
+	}
+)
+
+// FamilyOf returns the owner mapping family of the AMI type, or empty string if unknown
+func FamilyOf(amiType string, autoMode bool) string {
+	t, _ := constants.LookupAmiType(amiType, autoMode)
+	return t.OwnerFamily
+}
+
+// IsValidOwnerId reports whether v looks like a 12-digit AWS account ID
+func IsValidOwnerId(v string) bool {
+	if len(v) != 12 {
+		return false
+	}
+	for _, char := range v {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// SSMGetParameterAPIClient is the subset of the SSM client used for discovery
+type SSMGetParameterAPIClient interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// BuiltinMappings returns the compiled-in owner mapping table of the family
+func BuiltinMappings(family string) (map[string]string, error) {
+	switch family {
+	case FamilyAmazonLinux:
+		return constants.AwsAccountMappingsAL, nil
+	case FamilyBottlerocket:
+		return constants.AwsAccountMappingsBottlerocket, nil
+	case FamilyWindows:
+		return constants.AwsAccountMappingsWindows, nil
+	case FamilyAutoMode:
+		return constants.AwsAccountMappingsAutoMode, nil
+	}
+	return nil, fmt.Errorf("unsupported family '%s'. Valid families: %v", family, Families)
+}
+
+// variableName returns the name of the compiled-in mapping table in pkg/constants
+func variableName(family string) string {
+	switch family {
+	case FamilyAmazonLinux:
+		return "AwsAccountMappingsAL"
+	case FamilyBottlerocket:
+		return "AwsAccountMappingsBottlerocket"
+	case FamilyWindows:
+		return "AwsAccountMappingsWindows"
+	case FamilyAutoMode:
+		return "AwsAccountMappingsAutoMode"
+	}
+	return ""
+}
+
+// LookupOwner returns the owner of the region from mappings, "*" is the fallback entry for the rest of regions
+func LookupOwner(mappings map[string]string, region string) (string, bool) {
+	if v, ok := mappings[region]; ok {
+		return v, true
+	}
+	v, ok := mappings["*"]
+	return v, ok
+}
+
+// ParameterName returns the SSM public parameter holding the recommended AMI ID of the family,
+// same parameters as used by hack/ami-owner-info-check.sh
+func ParameterName(family, kubernetesVersion string) (string, error) {
+	switch family {
+	case FamilyAmazonLinux:
+		return fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2023/x86_64/standard/recommended/image_id", kubernetesVersion), nil
+	case FamilyBottlerocket:
+		return fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s/x86_64/latest/image_id", kubernetesVersion), nil
+	case FamilyWindows:
+		return fmt.Sprintf("/aws/service/ami-windows-latest/Windows_Server-2025-English-Core-EKS_Optimized-%s/image_id", kubernetesVersion), nil
+	}
+	return "", fmt.Errorf("family '%s' could not be discovered. Valid families: %v", family, DiscoverableFamilies)
+}
+
+// Discover resolves the recommended AMI ID of the family from SSM, then looks up its owner with DescribeImages.
+// Both clients must be configured for the same region.
+func Discover(ctx context.Context, ssmSvc SSMGetParameterAPIClient, ec2Svc ec2.DescribeImagesAPIClient, family, kubernetesVersion string) (imageId, ownerId string, err error) {
+	name, err := ParameterName(family, kubernetesVersion)
+	if err != nil {
+		return "", "", err
+	}
+
+	param, err := ssmSvc.GetParameter(ctx, &ssm.GetParameterInput{Name: aws.String(name)})
+	if err != nil {
+		return "", "", fmt.Errorf("AWS SSM GetParameter failed: %v", err)
+	}
+
+	if param.Parameter != nil {
+		imageId = aws.ToString(param.Parameter.Value)
+	}
+	if imageId == "" || imageId == "None" {
+		return "", "", fmt.Errorf("AMI not found")
+	}
+
+	out, err := ec2Svc.DescribeImages(ctx, &ec2.DescribeImagesInput{
+		ImageIds:          []string{imageId},
+		IncludeDeprecated: aws.Bool(true),
+	})
+	if err != nil {
+		return imageId, "", fmt.Errorf("failed to retrieve owner: %v", err)
+	}
+	if len(out.Images) == 0 {
+		return imageId, "", fmt.Errorf("failed to retrieve owner: %s not found", imageId)
+	}
+
+	return imageId, aws.ToString(out.Images[0].OwnerId), nil
+}
+
+// Merge returns a copy of builtin with discovered owners applied, regions which are already
+// covered by the "*" fallback entry are left out.
+func Merge(builtin map[string]string, discovered map[string]string) map[string]string {
+	merged := make(map[string]string, len(builtin)+len(discovered))
+	for region, owner := range builtin {
+		merged[region] = owner
+	}
+	for region, owner := range discovered {
+		if _, ok := builtin[region]; !ok && builtin["*"] == owner {
+			continue
+		}
+		merged[region] = owner
+	}
+	return merged
+}
+
+// GoSource renders mappings as a gofmt'ed Go file of package constants, the variable could be pasted
+// over the compiled-in mapping table of the family
+func GoSource(family string, mappings map[string]string) ([]byte, error) {
+	name := variableName(family)
+	if name == "" {
+		return nil, fmt.Errorf("unsupported family '%s'. Valid families: %v", family, Families)
+	}
+
+	regions := make([]string, 0, len(mappings))
+	for region := range mappings {
+		// "*" is always the last entry
+		if region != "*" {
+			regions = append(regions, region)
+		}
+	}
+	slices.Sort(regions)
+	if _, ok := mappings["*"]; ok {
+		regions = append(regions, "*")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "package constants\n\n")
+	fmt.Fprintf(&buf, "// %s is generated by \"%s owners discover --family %s\"\n", name, constants.NAME, family)
+	fmt.Fprintf(&buf, "var %s = map[string]string{\n", name)
+	for _, region := range regions {
+		fmt.Fprintf(&buf, "%q: %q,\n", region, mappings[region])
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,217 @@
+package owners
+
+import (
+	"context"
+	"errors"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+type fakeSSM struct {
+	out  *ssm.GetParameterOutput
+	err  error
+	name string // name of the last parameter looked up
+}
+
+func (f *fakeSSM) GetParameter(ctx context.Context, params *ssm.GetParameterInput, _ ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	f.name = aws.ToString(params.Name)
+	return f.out, f.err
+}
+
+type fakeEC2 struct {
+	images []ec2types.Image
+	err    error
+	input  *ec2.DescribeImagesInput
+}
+
+func (f *fakeEC2) DescribeImages(ctx context.Context, params *ec2.DescribeImagesInput, _ ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error) {
+	f.input = params
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &ec2.DescribeImagesOutput{Images: f.images}, nil
+}
+
+func parameter(value *string) *ssm.GetParameterOutput {
+	return &ssm.GetParameterOutput{Parameter: &ssmtypes.Parameter{Value: value}}
+}
+
+func TestDiscover(t *testing.T) {
+	image := ec2types.Image{ImageId: aws.String("ami-0123456789abcdef0"), OwnerId: aws.String("602401143452")}
+
+	tests := []struct {
+		name      string
+		ssm       *fakeSSM
+		ec2       *fakeEC2
+		wantImage string
+		wantOwner string
+		wantErr   string
+	}{
+		{
+			name:      "owner of the recommended AMI",
+			ssm:       &fakeSSM{out: parameter(aws.String("ami-0123456789abcdef0"))},
+			ec2:       &fakeEC2{images: []ec2types.Image{image}},
+			wantImage: "ami-0123456789abcdef0",
+			wantOwner: "602401143452",
+		},
+		{
+			name:    "SSM failure",
+			ssm:     &fakeSSM{err: errors.New("AccessDenied")},
+			ec2:     &fakeEC2{},
+			wantErr: "AWS SSM GetParameter failed: AccessDenied",
+		},
+		{
+			name:    "nil parameter",
+			ssm:     &fakeSSM{out: &ssm.GetParameterOutput{}},
+			ec2:     &fakeEC2{},
+			wantErr: "AMI not found",
+		},
+		{
+			name:    "nil parameter value",
+			ssm:     &fakeSSM{out: parameter(nil)},
+			ec2:     &fakeEC2{},
+			wantErr: "AMI not found",
+		},
+		{
+			name:    "parameter without AMI",
+			ssm:     &fakeSSM{out: parameter(aws.String("None"))},
+			ec2:     &fakeEC2{},
+			wantErr: "AMI not found",
+		},
+		{
+			name:      "DescribeImages failure",
+			ssm:       &fakeSSM{out: parameter(aws.String("ami-0123456789abcdef0"))},
+			ec2:       &fakeEC2{err: errors.New("UnauthorizedOperation")},
+			wantImage: "ami-0123456789abcdef0",
+			wantErr:   "failed to retrieve owner: UnauthorizedOperation",
+		},
+		{
+			name:      "AMI not visible",
+			ssm:       &fakeSSM{out: parameter(aws.String("ami-0123456789abcdef0"))},
+			ec2:       &fakeEC2{},
+			wantImage: "ami-0123456789abcdef0",
+			wantErr:   "failed to retrieve owner: ami-0123456789abcdef0 not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imageId, ownerId, err := Discover(context.Background(), tt.ssm, tt.ec2, FamilyAmazonLinux, "1.35")
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Discover() error = %v, want %s", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Discover() error = %v", err)
+			}
+			if imageId != tt.wantImage || ownerId != tt.wantOwner {
+				t.Errorf("Discover() = %s, %s, want %s, %s", imageId, ownerId, tt.wantImage, tt.wantOwner)
+			}
+
+			want, _ := ParameterName(FamilyAmazonLinux, "1.35")
+			if tt.ssm.name != want {
+				t.Errorf("GetParameter() name = %s, want %s", tt.ssm.name, want)
+			}
+			if tt.ec2.input != nil && !aws.ToBool(tt.ec2.input.IncludeDeprecated) {
+				t.Errorf("DescribeImages() must include deprecated AMIs")
+			}
+		})
+	}
+}
+
+func TestDiscoverUndiscoverableFamily(t *testing.T) {
+	ssmSvc := &fakeSSM{}
+	if _, _, err := Discover(context.Background(), ssmSvc, &fakeEC2{}, FamilyAutoMode, "1.35"); err == nil {
+		t.Error("Discover() error = nil, want an error for a family without SSM parameters")
+	}
+	if ssmSvc.name != "" {
+		t.Errorf("GetParameter() called with %s, want no call", ssmSvc.name)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	builtin := map[string]string{
+		"ap-east-1": "800184023465",
+		"*":         "602401143452",
+	}
+	discovered := map[string]string{
+		"ap-east-1":    "800184023465",
+		"us-east-1":    "602401143452", // covered by the fallback entry
+		"me-central-1": "759879836304",
+	}
+
+	merged := Merge(builtin, discovered)
+	want := map[string]string{
+		"ap-east-1":    "800184023465",
+		"me-central-1": "759879836304",
+		"*":            "602401143452",
+	}
+	if len(merged) != len(want) {
+		t.Errorf("Merge() = %v, want %v", merged, want)
+	}
+	for region, owner := range want {
+		if merged[region] != owner {
+			t.Errorf("Merge()[%s] = %s, want %s", region, merged[region], owner)
+		}
+	}
+	if _, ok := builtin["me-central-1"]; ok {
+		t.Error("Merge() modified the built-in mappings")
+	}
+}
+
+func TestGoSourceCompiles(t *testing.T) {
+	for _, family := range Families {
+		t.Run(family, func(t *testing.T) {
+			builtin, err := BuiltinMappings(family)
+			if err != nil {
+				t.Fatal(err)
+			}
+			src, err := GoSource(family, builtin)
+			if err != nil {
+				t.Fatalf("GoSource() error = %v", err)
+			}
+
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, family+".go", src, 0)
+			if err != nil {
+				t.Fatalf("GoSource() output does not parse: %v\n%s", err, src)
+			}
+			conf := types.Config{Importer: importer.Default()}
+			if _, err := conf.Check("constants", fset, []*ast.File{file}, nil); err != nil {
+				t.Fatalf("GoSource() output does not compile: %v\n%s", err, src)
+			}
+
+			out := string(src)
+			if !strings.HasPrefix(out, "package constants\n") {
+				t.Errorf("GoSource() output lacks the package clause:\n%s", out)
+			}
+			if !strings.Contains(out, "var "+variableName(family)+" = map[string]string{") {
+				t.Errorf("GoSource() output lacks the variable declaration:\n%s", out)
+			}
+			// The fallback entry always comes last
+			if _, ok := builtin["*"]; ok {
+				lines := strings.Split(strings.TrimSpace(out), "\n")
+				if !strings.Contains(lines[len(lines)-2], `"*"`) {
+					t.Errorf("GoSource() fallback entry is not the last one:\n%s", out)
+				}
+			}
+		})
+	}
+}
+
+func TestGoSourceUnknownFamily(t *testing.T) {
+	if _, err := GoSource("Ubuntu", map[string]string{"*": "099720109477"}); err == nil {
+		t.Error("GoSource() error = nil, want an error for an unknown family")
+	}
+}